@@ -0,0 +1,83 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/MattiaPun/SubTUI/v2/internal/api"
+	"github.com/MattiaPun/SubTUI/v2/internal/playlist"
+)
+
+const usage = `Usage:
+  subtui [-debug] [-v] [-mini]
+  subtui playlist sync [-y] <dir>`
+
+// Runs a subcommand and returns the exit code
+func runCommand(args []string) int {
+	if len(args) >= 2 && args[0] == "playlist" && args[1] == "sync" {
+		syncFlags := flag.NewFlagSet("playlist sync", flag.ExitOnError)
+		yes := syncFlags.Bool("y", false, "Apply the changes without asking")
+		_ = syncFlags.Parse(args[2:])
+
+		if syncFlags.NArg() != 1 {
+			fmt.Println(usage)
+			return 2
+		}
+
+		if err := playlistSync(syncFlags.Arg(0), *yes); err != nil {
+			fmt.Println("Error:", err)
+			return 1
+		}
+
+		return 0
+	}
+
+	fmt.Println(usage)
+	return 2
+}
+
+func playlistSync(dir string, yes bool) error {
+	if api.AppServerConfig.Server.URL == "" || api.AppServerConfig.Server.Username == "" {
+		return fmt.Errorf("no server configured, log in with subtui first")
+	}
+
+	if err := api.SubsonicLoginCheck(); err != nil {
+		return err
+	}
+
+	plans, err := playlist.PlanSync(playlist.ExpandPath(dir))
+	if err != nil {
+		return err
+	}
+
+	changed := false
+	for _, plan := range plans {
+		fmt.Println(strings.Join(plan.Summary(), "\n"))
+		changed = changed || plan.Changed()
+	}
+
+	if !changed {
+		fmt.Println("All playlists are in sync")
+		return nil
+	}
+
+	if !yes {
+		fmt.Print("Apply these changes? [y/N] ")
+
+		answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+		if strings.ToLower(strings.TrimSpace(answer)) != "y" {
+			fmt.Println("Aborted")
+			return nil
+		}
+	}
+
+	if err := playlist.ApplySync(plans); err != nil {
+		return err
+	}
+
+	fmt.Println("Playlists synced")
+	return nil
+}
@@ -90,10 +90,37 @@ func subsonicGET(endpoint string, params map[string]string) (*SubsonicResponse,
 		log.Printf("[API] Connection Failed: %v", err)
 		return nil, err
 	}
+
+	return decodeResponse(resp, redactURL(fullUrl))
+}
+
+// Helper: POST request with form encoded parameters, for requests with too many ids to fit in a URL
+func subsonicPOSTValues(endpoint string, params url.Values) (*SubsonicResponse, error) {
+	baseUrl := AppServerConfig.Server.URL + "/rest" + endpoint
+
+	v := getAuthParams()
+
+	for key, values := range params {
+		for _, value := range values {
+			v.Add(key, value)
+		}
+	}
+
+	log.Printf("[API] Request: POST %s (%d parameters)", baseUrl, len(v))
+	resp, err := httpClient.PostForm(baseUrl, v)
+	if err != nil {
+		log.Printf("[API] Connection Failed: %v", err)
+		return nil, err
+	}
+
+	return decodeResponse(resp, baseUrl)
+}
+
+func decodeResponse(resp *http.Response, logUrl string) (*SubsonicResponse, error) {
 	defer func() { _ = resp.Body.Close() }()
 
 	if resp.StatusCode != http.StatusOK {
-		log.Printf("[API] HTTP Error: %d | URL: %s", resp.StatusCode, redactURL(fullUrl))
+		log.Printf("[API] HTTP Error: %d | URL: %s", resp.StatusCode, logUrl)
 		return nil, fmt.Errorf("server error (HTTP %d)", resp.StatusCode)
 	}
 
@@ -186,6 +213,22 @@ func SubsonicSearchSong(query string, offset int) ([]Song, error) {
 	return data.Response.SearchResult.Songs, nil
 }
 
+func SubsonicSearchAll(query string, artistCount int, albumCount int, songCount int) (SearchResult3, error) {
+	params := map[string]string{
+		"query":       query,
+		"artistCount": strconv.Itoa(artistCount),
+		"albumCount":  strconv.Itoa(albumCount),
+		"songCount":   strconv.Itoa(songCount),
+	}
+
+	data, err := subsonicGET("/search3", params)
+	if err != nil {
+		return SearchResult3{}, err
+	}
+
+	return data.Response.SearchResult, nil
+}
+
 func SubsonicGetPlaylistSongs(id string) ([]Song, error) {
 	params := map[string]string{
 		"id": id,
@@ -223,6 +266,19 @@ func SubsonicGetAlbum(id string) ([]Song, error) {
 	return data.Response.Album.Songs, nil
 }
 
+func SubsonicGetSong(id string) (Song, error) {
+	params := map[string]string{
+		"id": id,
+	}
+
+	data, err := subsonicGET("/getSong", params)
+	if err != nil {
+		return Song{}, err
+	}
+
+	return data.Response.Song, nil
+}
+
 func SubsonicGetAlbumList(searchType string, offset int) ([]Album, error) {
 	params := map[string]string{
 		"type":   searchType,
@@ -251,6 +307,95 @@ func SubsonicGetArtist(id string) ([]Album, error) {
 	return data.Response.Artist.Albums, nil
 }
 
+func SubsonicGetSimilarSongs(id string, count int) ([]Song, error) {
+	params := map[string]string{
+		"id":    id,
+		"count": strconv.Itoa(count),
+	}
+
+	data, err := subsonicGET("/getSimilarSongs", params)
+	if err != nil {
+		return nil, err
+	}
+
+	return data.Response.SimilarSongs.Songs, nil
+}
+
+func SubsonicGetTopSongs(artist string, count int) ([]Song, error) {
+	params := map[string]string{
+		"artist": artist,
+		"count":  strconv.Itoa(count),
+	}
+
+	data, err := subsonicGET("/getTopSongs", params)
+	if err != nil {
+		return nil, err
+	}
+
+	return data.Response.TopSongs.Songs, nil
+}
+
+func SubsonicGetSimilarSongs2(artistID string, count int) ([]Song, error) {
+	params := map[string]string{
+		"id":    artistID,
+		"count": strconv.Itoa(count),
+	}
+
+	data, err := subsonicGET("/getSimilarSongs2", params)
+	if err != nil {
+		return nil, err
+	}
+
+	return data.Response.SimilarSongs2.Songs, nil
+}
+
+func SubsonicGetRandomSongs(size int, genre string, fromYear int, toYear int) ([]Song, error) {
+	params := map[string]string{
+		"size": strconv.Itoa(size),
+	}
+
+	if genre != "" {
+		params["genre"] = genre
+	}
+	if fromYear > 0 {
+		params["fromYear"] = strconv.Itoa(fromYear)
+	}
+	if toYear > 0 {
+		params["toYear"] = strconv.Itoa(toYear)
+	}
+
+	data, err := subsonicGET("/getRandomSongs", params)
+	if err != nil {
+		return nil, err
+	}
+
+	return data.Response.RandomSongs.Songs, nil
+}
+
+func SubsonicGetGenres() ([]Genre, error) {
+	data, err := subsonicGET("/getGenres", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return data.Response.Genres.Genres, nil
+}
+
+func SubsonicGetSongsByGenre(genre string, offset int) ([]Song, error) {
+	params := map[string]string{
+		"genre":  genre,
+		"count":  "150",
+		"offset": strconv.Itoa(offset),
+	}
+
+	data, err := subsonicGET("/getSongsByGenre", params)
+	if err != nil {
+		return nil, err
+	}
+
+	return data.Response.SongsByGenre.Songs, nil
+}
+
 func SubsonicStar(id string) {
 	params := map[string]string{
 		"id": id,
@@ -302,6 +447,14 @@ func SubsonicStream(id string) string {
 	return fullUrl
 }
 
+// SubsonicStreamLocation is a stream URL without credentials, for files that are shared or kept around
+func SubsonicStreamLocation(id string) string {
+	v := url.Values{}
+	v.Set("id", id)
+
+	return AppServerConfig.Server.URL + "/rest/stream?" + v.Encode()
+}
+
 func SubsonicScrobble(id string, submission bool) {
 	time := strconv.FormatInt(time.Now().UTC().UnixMilli(), 10)
 
@@ -327,7 +480,11 @@ func SubsonicCoverArtUrl(id string, size int) string {
 }
 
 func SubsonicCoverArt(id string) ([]byte, error) {
-	url := SubsonicCoverArtUrl(id, 50)
+	return SubsonicCoverArtSized(id, 50)
+}
+
+func SubsonicCoverArtSized(id string, size int) ([]byte, error) {
+	url := SubsonicCoverArtUrl(id, size)
 	resp, err := httpClient.Get(url)
 	if err != nil {
 		return nil, err
@@ -374,13 +531,68 @@ func SubsonicGetQueue() (*PlayQueue, error) {
 	return &data.Response.PlayQueue, nil
 }
 
-func SubsonicAddToPlaylist(songID string, playlistID string) {
-	params := map[string]string{
-		"playlistId":  playlistID,
-		"songIdToAdd": songID,
+func SubsonicAddToPlaylist(songIDs []string, playlistID string) error {
+	v := url.Values{}
+
+	v.Set("playlistId", playlistID)
+	for _, id := range songIDs {
+		v.Add("songIdToAdd", id)
+	}
+
+	data, err := subsonicPOSTValues("/updatePlaylist", v)
+	if err != nil {
+		return err
+	}
+
+	if data.Response.Error != nil {
+		return fmt.Errorf("could not add to playlist: %s", data.Response.Error.Message)
+	}
+
+	return nil
+}
+
+func SubsonicCreatePlaylist(name string, songIDs []string) error {
+	v := url.Values{}
+
+	v.Set("name", name)
+	for _, id := range songIDs {
+		v.Add("songId", id)
+	}
+
+	data, err := subsonicPOSTValues("/createPlaylist", v)
+	if err != nil {
+		return err
 	}
 
-	_, _ = subsonicGET("/updatePlaylist", params)
+	if data.Response.Error != nil {
+		return fmt.Errorf("could not create playlist %s: %s", name, data.Response.Error.Message)
+	}
+
+	return nil
+}
+
+// Replaces the songs of a playlist, removeCount is the current number of songs
+func SubsonicReplacePlaylistSongs(playlistID string, removeCount int, songIDs []string) error {
+	v := url.Values{}
+
+	v.Set("playlistId", playlistID)
+	for i := 0; i < removeCount; i++ {
+		v.Add("songIndexToRemove", strconv.Itoa(i))
+	}
+	for _, id := range songIDs {
+		v.Add("songIdToAdd", id)
+	}
+
+	data, err := subsonicPOSTValues("/updatePlaylist", v)
+	if err != nil {
+		return err
+	}
+
+	if data.Response.Error != nil {
+		return fmt.Errorf("could not update playlist: %s", data.Response.Error.Message)
+	}
+
+	return nil
 }
 
 func SubsonicCreateShare(ID string) (string, error) {
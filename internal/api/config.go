@@ -20,11 +20,13 @@ var defaultServerConfig []byte
 var AppServerConfig ServerConfig
 
 type Config struct {
-	App      App      `toml:"app"`
-	Theme    Theme    `toml:"theme" comment:"Format: ['Light color', 'Dark color']"`
-	Filters  Filters  `toml:"filters"`
-	Keybinds Keybinds `toml:"keybinds"`
-	Columns  Columns  `toml:"columns"`
+	App            App             `toml:"app"`
+	Theme          Theme           `toml:"theme" comment:"Format: ['Light color', 'Dark color']"`
+	Filters        Filters         `toml:"filters"`
+	FilterProfiles []FilterProfile `toml:"filter_profiles" comment:"Named sets of filters to switch between, see the README for an example"`
+	SmartPlaylists []SmartPlaylist `toml:"smart_playlists" comment:"Playlists built from rules, see the README for an example"`
+	Keybinds       Keybinds        `toml:"keybinds"`
+	Columns        Columns         `toml:"columns"`
 }
 
 type ServerConfig struct {
@@ -43,10 +45,27 @@ type Security struct {
 }
 
 type App struct {
-	ReplayGain    string `toml:"replaygain" comment:"Type of replaygain: 'track', 'album', 'no'"`
-	Notifications bool   `toml:"desktop_notifications"`
-	DiscordRPC    bool   `toml:"discord_rich_presence"`
-	MouseSupport  bool   `toml:"mouse_support"`
+	ReplayGain            string  `toml:"replaygain" comment:"Type of replaygain: 'track', 'album', 'auto', 'no'"`
+	ReplayGainPreamp      float64 `toml:"replaygain_preamp" comment:"Pre-amplification applied on top of replaygain (in dB)"`
+	ReplayGainClip        bool    `toml:"replaygain_clipping_prevention" comment:"Lower the gain to prevent clipping caused by replaygain"`
+	LoudnessNormalization string  `toml:"loudness_normalization" comment:"Normalization for tracks without replaygain tags: 'loudnorm', 'dynaudnorm', 'no'"`
+	Notifications         bool    `toml:"desktop_notifications"`
+	DiscordRPC            bool    `toml:"discord_rich_presence"`
+	MouseSupport          bool    `toml:"mouse_support"`
+	LevelMeter            bool    `toml:"level_meter" comment:"Show a stereo level meter next to the progress bar"`
+	Shuffle               string  `toml:"shuffle" comment:"Shuffle strategy: 'random', 'smart'"`
+	ShuffleFavorRated     bool    `toml:"shuffle_favor_rated" comment:"Smart shuffle plays higher rated and starred songs more often"`
+	AutoContinue          bool    `toml:"auto_continue" comment:"Add similar songs when the end of the queue is reached"`
+	AutoContinueCount     int     `toml:"auto_continue_count" comment:"Number of songs added each time the queue runs out"`
+	RadioSize             int     `toml:"radio_size" comment:"Number of songs in a radio queue"`
+	VolumeStep            int     `toml:"volume_step" comment:"Volume change per keypress (in percent)"`
+	VolumeMax             int     `toml:"volume_max" comment:"Maximum volume (in percent), values above 100 amplify the audio"`
+	PlaylistDir           string  `toml:"playlist_dir" comment:"Directory with local .m3u/.m3u8 playlists to sync to the server"`
+	ExportLocation        string  `toml:"export_location" comment:"Song locations in exported playlists: 'path' (relative server path), 'stream' (stream URL without credentials)"`
+	HideFiltered          bool    `toml:"hide_filtered" comment:"Hide songs excluded by the filters instead of dimming them, in lists and the queue"`
+	FindNarrow            bool    `toml:"find_narrow" comment:"Let the in-list finder hide non-matching rows instead of only highlighting matches"`
+	SearchDebounce        int     `toml:"search_debounce" comment:"Search while typing after this pause (in milliseconds), 0 to only search on enter"`
+	AlbumArt              string  `toml:"album_art" comment:"Cover rendering: 'auto', 'kitty', 'sixel', 'iterm', 'blocks', 'off'"`
 }
 
 type Theme struct {
@@ -67,6 +86,30 @@ type Filters struct {
 	MaxPlayCount     int      `toml:"max_play_count" comment:"Exclude songs with a play count less than or equal to this number, 0 to disable"`
 	ExcludeFavorites bool     `toml:"exclude_favorites" comment:"Set to true to exclude songs that are marked as a favorite/starred"`
 	MaxRating        int      `toml:"max_rating" comment:"Exclude songs with a rating less than or equal to this number (1-5), 0 to disable"`
+	Rules            []string `toml:"rules" comment:"Exclude songs matching any of these queries, see the README for the syntax"`
+}
+
+type FilterProfile struct {
+	Name string `toml:"name"`
+	Filters
+}
+
+type SmartPlaylist struct {
+	Name         string   `toml:"name"`
+	Source       string   `toml:"source" comment:"Where songs come from: 'library', 'random', 'starred'"`
+	Genres       []string `toml:"genres" comment:"Only songs belonging to these genres"`
+	Artists      []string `toml:"artists" comment:"Only songs by these artists"`
+	Paths        []string `toml:"paths" comment:"Only songs whose file path contains these strings"`
+	MinYear      int      `toml:"min_year"`
+	MaxYear      int      `toml:"max_year"`
+	MinRating    int      `toml:"min_rating"`
+	MinPlayCount int      `toml:"min_play_count"`
+	MaxPlayCount int      `toml:"max_play_count"`
+	Starred      bool     `toml:"starred" comment:"Only songs marked as a favorite/starred"`
+	MinDuration  int      `toml:"min_duration" comment:"In seconds"`
+	MaxDuration  int      `toml:"max_duration" comment:"In seconds"`
+	Query        string   `toml:"query" comment:"Only songs matching this query, see the README for the syntax"`
+	Limit        int      `toml:"limit" comment:"Maximum number of songs, 0 for no limit"`
 }
 
 type Columns struct {
@@ -89,6 +132,7 @@ type Keybinds struct {
 	Media      MediaKeybinds      `toml:"media"`
 	Queue      QueueKeybinds      `toml:"queue"`
 	Favorites  FavoriteKeybinds   `toml:"favorites"`
+	NowPlaying NowPlayingKeybinds `toml:"now_playing"`
 	Other      OtherKeybinds      `toml:"other"`
 }
 
@@ -108,12 +152,24 @@ type NavigationKeybinds struct {
 	Bottom       []string `toml:"bottom"`
 	Select       []string `toml:"select"`
 	PlayShuffled []string `toml:"play_shuffeled"`
+	ToggleSelect []string `toml:"toggle_select"`
+	VisualMode   []string `toml:"visual_mode"`
+	SortBy       []string `toml:"sort_by"`
+	SortReverse  []string `toml:"sort_reverse"`
+	ViewBack     []string `toml:"view_back"`
+	ViewForward  []string `toml:"view_forward"`
 }
 
 type SearchKeybinds struct {
 	FocusSearch []string `toml:"focus_search"`
+	FilterList  []string `toml:"filter_list"`
+	Find        []string `toml:"find"`
+	FindNext    []string `toml:"find_next"`
+	FindPrev    []string `toml:"find_prev"`
 	FilterNext  []string `toml:"filter_next"`
 	FilterPrev  []string `toml:"filter_prev"`
+	HistoryPrev []string `toml:"history_prev"`
+	HistoryNext []string `toml:"history_next"`
 }
 
 type LibraryKeybinds struct {
@@ -121,6 +177,10 @@ type LibraryKeybinds struct {
 	AddRating     []string `toml:"add_rating"`
 	GoToAlbum     []string `toml:"go_to_album"`
 	GoToArtist    []string `toml:"go_to_artist"`
+	StartRadio    []string `toml:"start_radio"`
+	SyncPlaylists []string `toml:"sync_playlists"`
+	RefreshSmart  []string `toml:"refresh_smart"`
+	SaveSearch    []string `toml:"save_search"`
 }
 
 type MediaKeybinds struct {
@@ -134,6 +194,7 @@ type MediaKeybinds struct {
 	Forward    []string `toml:"forward"`
 	VolumeUp   []string `toml:"volume_up"`
 	VolumeDown []string `toml:"volume_down"`
+	Mute       []string `toml:"mute"`
 }
 
 type QueueKeybinds struct {
@@ -144,6 +205,12 @@ type QueueKeybinds struct {
 	ClearQueue      []string `toml:"clear_queue"`
 	MoveUp          []string `toml:"move_up"`
 	MoveDown        []string `toml:"move_down"`
+	Undo            []string `toml:"undo"`
+	Redo            []string `toml:"redo"`
+	RemoveUpNext    []string `toml:"remove_up_next"`
+	ClearUpNext     []string `toml:"clear_up_next"`
+	Export          []string `toml:"export"`
+	Import          []string `toml:"import"`
 }
 
 type FavoriteKeybinds struct {
@@ -151,9 +218,23 @@ type FavoriteKeybinds struct {
 	ViewFavorites  []string `toml:"view_favorites"`
 }
 
+type NowPlayingKeybinds struct {
+	Toggle    []string `toml:"toggle"`
+	RateUp    []string `toml:"rate_up"`
+	RateDown  []string `toml:"rate_down"`
+	GoToAlbum []string `toml:"go_to_album"`
+}
+
 type OtherKeybinds struct {
 	ToggleNotifications []string `toml:"toggle_notifications"`
 	CreateShareLink     []string `toml:"create_share_link"`
+	ToggleLevelMeter    []string `toml:"toggle_level_meter"`
+	ViewHistory         []string `toml:"view_history"`
+	ViewStats           []string `toml:"view_stats"`
+	FilterProfiles      []string `toml:"filter_profiles"`
+	ToggleHideFiltered  []string `toml:"toggle_hide_filtered"`
+	WhyFiltered         []string `toml:"why_filtered"`
+	ToggleMini          []string `toml:"toggle_mini"`
 }
 
 func GetConfigPath(configName string) string {
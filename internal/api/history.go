@@ -0,0 +1,97 @@
+package api
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+const (
+	HistoryStart    = "start"
+	HistoryScrobble = "scrobble"
+	HistorySkip     = "skip"
+	HistoryFinish   = "finish"
+)
+
+type HistoryEvent struct {
+	Time     time.Time `json:"time"`
+	Type     string    `json:"type"`
+	SongID   string    `json:"songId"`
+	Title    string    `json:"title"`
+	Artist   string    `json:"artist"`
+	Album    string    `json:"album"`
+	AlbumID  string    `json:"albumId"`
+	Genre    string    `json:"genre"`
+	Duration int       `json:"duration"`
+	Listened float64   `json:"listened,omitempty"`
+}
+
+var historyMutex sync.Mutex
+
+func AppendHistory(event HistoryEvent) error {
+	historyPath := GetConfigPath("history.jsonl")
+	if historyPath == "" {
+		return fmt.Errorf("could not determine history path")
+	}
+
+	line, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("could not encode history event: %v", err)
+	}
+
+	historyMutex.Lock()
+	defer historyMutex.Unlock()
+
+	historyFile, err := os.OpenFile(historyPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("could not open history file: %v", err)
+	}
+	defer func() { _ = historyFile.Close() }()
+
+	if _, err := historyFile.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("could not write history file: %v", err)
+	}
+
+	return nil
+}
+
+func LoadHistory() ([]HistoryEvent, error) {
+	historyPath := GetConfigPath("history.jsonl")
+	if historyPath == "" {
+		return nil, fmt.Errorf("could not determine history path")
+	}
+
+	historyMutex.Lock()
+	defer historyMutex.Unlock()
+
+	historyFile, err := os.Open(historyPath)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("could not open history file: %v", err)
+	}
+	defer func() { _ = historyFile.Close() }()
+
+	var events []HistoryEvent
+
+	scanner := bufio.NewScanner(historyFile)
+	for scanner.Scan() {
+		var event HistoryEvent
+
+		// Skip lines that were cut off by a crash
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			continue
+		}
+
+		events = append(events, event)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return events, fmt.Errorf("could not read history file: %v", err)
+	}
+
+	return events, nil
+}
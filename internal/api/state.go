@@ -0,0 +1,155 @@
+package api
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+
+	"github.com/pelletier/go-toml/v2"
+)
+
+var AppState State
+
+// Saves run in the background, one at a time, and older snapshots never overwrite newer ones
+var (
+	stateMu      sync.Mutex
+	stateTaken   uint64
+	stateWritten uint64
+)
+
+// StateSnapshot is a copy of the state that shares nothing with AppState
+type StateSnapshot struct {
+	state State
+	seq   uint64
+}
+
+type State struct {
+	Player  PlayerState `toml:"player"`
+	Queue   QueueState  `toml:"queue"`
+	Filters FilterState `toml:"filters"`
+	Search  SearchState `toml:"search"`
+}
+
+type PlayerState struct {
+	Volume int  `toml:"volume"`
+	Muted  bool `toml:"muted"`
+}
+
+type QueueState struct {
+	UpNext []string `toml:"up_next"`
+}
+
+type FilterState struct {
+	Profile string `toml:"profile"`
+}
+
+type SearchState struct {
+	History map[string][]string `toml:"history"`
+	Saved   []SavedSearch       `toml:"saved"`
+}
+
+type SavedSearch struct {
+	Query string `toml:"query"`
+	Mode  string `toml:"mode"`
+}
+
+func defaultState() State {
+	return State{
+		Player: PlayerState{
+			Volume: 100,
+			Muted:  false,
+		},
+	}
+}
+
+func LoadState() error {
+	AppState = defaultState()
+
+	statePath := GetConfigPath("state.toml")
+	if statePath == "" {
+		return fmt.Errorf("could not determine state path")
+	}
+
+	stateFile, err := os.ReadFile(statePath)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("could not open state file: %v", err)
+	}
+
+	if err := toml.Unmarshal(stateFile, &AppState); err != nil {
+		return fmt.Errorf("could not decode state file: %v", err)
+	}
+
+	return nil
+}
+
+// SnapshotState copies the state, call it where AppState is changed and save the copy elsewhere
+func SnapshotState() StateSnapshot {
+	state := AppState
+	state.Queue.UpNext = slices.Clone(AppState.Queue.UpNext)
+	state.Search.Saved = slices.Clone(AppState.Search.Saved)
+
+	if AppState.Search.History != nil {
+		state.Search.History = make(map[string][]string, len(AppState.Search.History))
+		for mode, history := range AppState.Search.History {
+			state.Search.History[mode] = slices.Clone(history)
+		}
+	}
+
+	stateMu.Lock()
+	stateTaken++
+	seq := stateTaken
+	stateMu.Unlock()
+
+	return StateSnapshot{state: state, seq: seq}
+}
+
+// SaveState writes a snapshot to a temporary file and renames it, so the state file is never left half written
+func SaveState(snapshot StateSnapshot) error {
+	stateMu.Lock()
+	defer stateMu.Unlock()
+
+	if snapshot.seq <= stateWritten {
+		return nil
+	}
+
+	statePath := GetConfigPath("state.toml")
+	if statePath == "" {
+		return fmt.Errorf("could not determine state path")
+	}
+
+	if err := os.MkdirAll(filepath.Dir(statePath), 0755); err != nil {
+		return err
+	}
+
+	data, err := toml.Marshal(snapshot.state)
+	if err != nil {
+		return err
+	}
+
+	tmpFile, err := os.CreateTemp(filepath.Dir(statePath), "state-*.toml")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.Remove(tmpFile.Name()) }()
+
+	if _, err := tmpFile.Write(data); err != nil {
+		_ = tmpFile.Close()
+		return err
+	}
+	if err := tmpFile.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmpFile.Name(), 0644); err != nil {
+		return err
+	}
+	if err := os.Rename(tmpFile.Name(), statePath); err != nil {
+		return err
+	}
+
+	stateWritten = snapshot.seq
+	return nil
+}
@@ -15,6 +15,7 @@ type SubsonicResponse struct {
 		Album struct {
 			Songs []Song `json:"song"`
 		} `json:"album"`
+		Song      Song `json:"song"`
 		AlbumList struct {
 			Albums []Album `json:"album"`
 		} `json:"albumList"`
@@ -26,6 +27,24 @@ type SubsonicResponse struct {
 			Album  []Album  `json:"album"`
 			Song   []Song   `json:"song"`
 		} `json:"starred2"`
+		SimilarSongs struct {
+			Songs []Song `json:"song"`
+		} `json:"similarSongs"`
+		SimilarSongs2 struct {
+			Songs []Song `json:"song"`
+		} `json:"similarSongs2"`
+		TopSongs struct {
+			Songs []Song `json:"song"`
+		} `json:"topSongs"`
+		RandomSongs struct {
+			Songs []Song `json:"song"`
+		} `json:"randomSongs"`
+		SongsByGenre struct {
+			Songs []Song `json:"song"`
+		} `json:"songsByGenre"`
+		Genres struct {
+			Genres []Genre `json:"genre"`
+		} `json:"genres"`
 		PlayQueue PlayQueue `json:"playQueue"`
 		Shares    struct {
 			ShareList []struct {
@@ -71,24 +90,44 @@ type Album struct {
 	Rating   int    `json:"userRating"`
 }
 
+type Genre struct {
+	Name      string `json:"value"`
+	SongCount int    `json:"songCount"`
+}
+
 type Song struct {
-	ID           string   `json:"id"`
-	Title        string   `json:"title"`
-	Artist       string   `json:"artist"`
-	ArtistID     string   `json:"artistId"`
-	AlbumArtists []Artist `json:"albumArtists"`
-	Album        string   `json:"album"`
-	AlbumID      string   `json:"albumId"`
-	Duration     int      `json:"duration"`
-	Rating       int      `json:"userRating"`
-	Genre        string   `json:"genre"`
-	Year         int      `json:"year"`
-	Note         string   `json:"comment"`
-	Path         string   `json:"path"`
-	PlayCount    int      `json:"playCount"`
-	TrackNumber  int      `json:"track"`
-	DiscNumber   int      `json:"discNumber"`
+	ID           string     `json:"id"`
+	Title        string     `json:"title"`
+	Artist       string     `json:"artist"`
+	ArtistID     string     `json:"artistId"`
+	AlbumArtists []Artist   `json:"albumArtists"`
+	Album        string     `json:"album"`
+	AlbumID      string     `json:"albumId"`
+	Duration     int        `json:"duration"`
+	Rating       int        `json:"userRating"`
+	Genre        string     `json:"genre"`
+	Year         int        `json:"year"`
+	Note         string     `json:"comment"`
+	Path         string     `json:"path"`
+	PlayCount    int        `json:"playCount"`
+	TrackNumber  int        `json:"track"`
+	DiscNumber   int        `json:"discNumber"`
+	Suffix       string     `json:"suffix"`
+	BitRate      int        `json:"bitRate"`
+	SamplingRate int        `json:"samplingRate"`
+	BitDepth     int        `json:"bitDepth"`
+	ReplayGain   ReplayGain `json:"replayGain"`
 	Filtered     bool
+	AutoAdded    bool
+	QueueEntry   int
+}
+
+// ReplayGain values as reported by OpenSubsonic servers, zero when missing
+type ReplayGain struct {
+	TrackGain float64 `json:"trackGain"`
+	AlbumGain float64 `json:"albumGain"`
+	TrackPeak float64 `json:"trackPeak"`
+	AlbumPeak float64 `json:"albumPeak"`
 }
 
 type Playlist struct {
@@ -0,0 +1,176 @@
+// Package art loads album covers and draws them in the terminal.
+package art
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/MattiaPun/SubTUI/v2/internal/api"
+)
+
+type Protocol int
+
+const (
+	Off Protocol = iota
+	Blocks
+	Kitty
+	Sixel
+	ITerm
+)
+
+// Size of covers fetched from the server, they are scaled down locally
+const fetchSize = 300
+
+// Number of decoded covers and rendered boxes kept in memory
+const cacheLimit = 64
+
+var (
+	cacheMu  sync.Mutex
+	images   = map[string]image.Image{}
+	rendered = map[string]string{}
+)
+
+// Detect picks the protocol from the album_art setting, 'auto' looks at the terminal
+func Detect(setting string) Protocol {
+	switch strings.ToLower(setting) {
+	case "off", "no":
+		return Off
+	case "blocks":
+		return Blocks
+	case "kitty":
+		return Kitty
+	case "sixel":
+		return Sixel
+	case "iterm":
+		return ITerm
+	}
+
+	term := os.Getenv("TERM")
+	program := os.Getenv("TERM_PROGRAM")
+
+	switch {
+	// Multiplexers don't pass images through
+	case os.Getenv("TMUX") != "" || strings.HasPrefix(term, "screen"):
+		return Blocks
+	case os.Getenv("KITTY_WINDOW_ID") != "" || term == "xterm-kitty" || term == "xterm-ghostty" || program == "ghostty":
+		return Kitty
+	case program == "iTerm.app" || program == "WezTerm" || os.Getenv("LC_TERMINAL") == "iTerm2":
+		return ITerm
+	case strings.HasPrefix(term, "foot") || term == "mlterm" || program == "contour":
+		return Sixel
+	}
+
+	return Blocks
+}
+
+// Load returns the cover with the given id, it is downloaded once and kept in the cache directory
+func Load(id string) (image.Image, error) {
+	cacheMu.Lock()
+	img, ok := images[id]
+	cacheMu.Unlock()
+	if ok {
+		return img, nil
+	}
+
+	path := cachePath(id)
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		data, err = api.SubsonicCoverArtSized(id, fetchSize)
+		if err != nil {
+			return nil, err
+		}
+
+		if path != "" && os.MkdirAll(filepath.Dir(path), 0755) == nil {
+			_ = os.WriteFile(path, data, 0644)
+		}
+	}
+
+	img, _, err = image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("could not decode cover %s: %v", id, err)
+	}
+
+	cacheMu.Lock()
+	if len(images) >= cacheLimit {
+		clear(images)
+	}
+	images[id] = img
+	cacheMu.Unlock()
+
+	return img, nil
+}
+
+func cachePath(id string) string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+
+	name := strings.NewReplacer("/", "_", "\\", "_", "..", "_").Replace(id)
+	return filepath.Join(dir, "subtui", "covers", name)
+}
+
+// Render draws a cover into a box of cols x rows cells. Blocks return the rows of the box,
+// the other protocols an escape sequence that draws the image at the cursor.
+// Kitty keeps one image per slot, so drawing a slot again replaces it.
+func Render(id string, img image.Image, protocol Protocol, slot int, cols int, rows int) string {
+	if img == nil || cols <= 0 || rows <= 0 || protocol == Off {
+		return ""
+	}
+
+	key := fmt.Sprintf("%s/%d/%d/%d/%d", id, protocol, slot, cols, rows)
+
+	cacheMu.Lock()
+	out, ok := rendered[key]
+	cacheMu.Unlock()
+	if ok {
+		return out
+	}
+
+	switch protocol {
+	case Blocks:
+		out = renderBlocks(img, cols, rows)
+	case Kitty:
+		out = renderKitty(img, slot, cols, rows)
+	case Sixel:
+		out = renderSixel(img, cols, rows)
+	case ITerm:
+		out = renderITerm(img, cols, rows)
+	}
+
+	cacheMu.Lock()
+	if len(rendered) >= cacheLimit {
+		clear(rendered)
+	}
+	rendered[key] = out
+	cacheMu.Unlock()
+
+	return out
+}
+
+// Place draws an image escape at a cell of the screen (0-based) and puts the cursor back
+func Place(escape string, x int, y int) string {
+	if escape == "" {
+		return ""
+	}
+
+	return fmt.Sprintf("\x1b7\x1b[%d;%dH%s\x1b8", y+1, x+1, escape)
+}
+
+// Clear removes the image of a slot, only needed for kitty where images outlive the text around them
+func Clear(protocol Protocol, slot int) string {
+	if protocol != Kitty {
+		return ""
+	}
+
+	return fmt.Sprintf("\x1b_Ga=d,d=I,i=%d,q=2\x1b\\", slot)
+}
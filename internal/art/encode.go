@@ -0,0 +1,228 @@
+package art
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"strings"
+)
+
+// Pixels per cell assumed when scaling images for the graphic protocols
+const (
+	cellWidth  = 10
+	cellHeight = 20
+)
+
+// Helper: Scale an image to fit w x h pixels keeping its aspect ratio, averaging the covered source pixels
+func resize(img image.Image, w int, h int) *image.RGBA {
+	bounds := img.Bounds()
+	srcW, srcH := bounds.Dx(), bounds.Dy()
+	if srcW == 0 || srcH == 0 || w <= 0 || h <= 0 {
+		return image.NewRGBA(image.Rect(0, 0, 0, 0))
+	}
+
+	if srcW*h > srcH*w {
+		h = max(1, srcH*w/srcW)
+	} else {
+		w = max(1, srcW*h/srcH)
+	}
+
+	src := image.NewRGBA(image.Rect(0, 0, srcW, srcH))
+	draw.Draw(src, src.Bounds(), img, bounds.Min, draw.Src)
+
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		y0, y1 := y*srcH/h, max((y+1)*srcH/h, y*srcH/h+1)
+
+		for x := 0; x < w; x++ {
+			x0, x1 := x*srcW/w, max((x+1)*srcW/w, x*srcW/w+1)
+
+			var r, g, b, a, n int
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					i := src.PixOffset(sx, sy)
+					r += int(src.Pix[i])
+					g += int(src.Pix[i+1])
+					b += int(src.Pix[i+2])
+					a += int(src.Pix[i+3])
+					n++
+				}
+			}
+
+			dst.SetRGBA(x, y, color.RGBA{uint8(r / n), uint8(g / n), uint8(b / n), uint8(a / n)})
+		}
+	}
+
+	return dst
+}
+
+func encodePNG(img image.Image) string {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return ""
+	}
+
+	return base64.StdEncoding.EncodeToString(buf.Bytes())
+}
+
+// Helper: Two pixels per cell with the upper half block, works in any terminal with true color
+func renderBlocks(img image.Image, cols int, rows int) string {
+	small := resize(img, cols, rows*2)
+	width, height := small.Bounds().Dx(), small.Bounds().Dy()
+	padLeft := (cols - width) / 2
+	padTop := (rows*2 - height) / 4
+
+	lines := make([]string, rows)
+	for row := 0; row < rows; row++ {
+		top := (row - padTop) * 2
+		if top < 0 || top >= height {
+			lines[row] = strings.Repeat(" ", cols)
+			continue
+		}
+
+		var line strings.Builder
+		line.WriteString(strings.Repeat(" ", padLeft))
+
+		for x := 0; x < width; x++ {
+			upper := small.RGBAAt(x, top)
+			fmt.Fprintf(&line, "\x1b[38;2;%d;%d;%dm", upper.R, upper.G, upper.B)
+
+			if top+1 < height {
+				lower := small.RGBAAt(x, top+1)
+				fmt.Fprintf(&line, "\x1b[48;2;%d;%d;%dm", lower.R, lower.G, lower.B)
+			}
+
+			line.WriteString("▀\x1b[0m")
+		}
+
+		line.WriteString(strings.Repeat(" ", cols-width-padLeft))
+		lines[row] = line.String()
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// Helper: Kitty graphics protocol, the terminal scales the image into the cells
+func renderKitty(img image.Image, slot int, cols int, rows int) string {
+	data := encodePNG(resize(img, cols*cellWidth, rows*cellHeight))
+	if data == "" {
+		return ""
+	}
+
+	// The payload is sent in chunks of at most 4096 bytes
+	var out strings.Builder
+	for i := 0; i < len(data); i += 4096 {
+		end := min(i+4096, len(data))
+		more := 0
+		if end < len(data) {
+			more = 1
+		}
+
+		if i == 0 {
+			fmt.Fprintf(&out, "\x1b_Ga=T,f=100,i=%d,p=1,c=%d,r=%d,C=1,z=-1,q=2,m=%d;%s\x1b\\", slot, cols, rows, more, data[i:end])
+		} else {
+			fmt.Fprintf(&out, "\x1b_Gm=%d;%s\x1b\\", more, data[i:end])
+		}
+	}
+
+	return out.String()
+}
+
+// Helper: iTerm2 inline image, also understood by WezTerm
+func renderITerm(img image.Image, cols int, rows int) string {
+	data := encodePNG(resize(img, cols*cellWidth, rows*cellHeight))
+	if data == "" {
+		return ""
+	}
+
+	size := base64.StdEncoding.DecodedLen(len(data))
+	return fmt.Sprintf("\x1b]1337;File=inline=1;size=%d;width=%d;height=%d;preserveAspectRatio=1:%s\a", size, cols, rows, data)
+}
+
+// Helper: Sixel image with a fixed palette of 6 levels per channel
+func renderSixel(img image.Image, cols int, rows int) string {
+	small := resize(img, cols*cellWidth, rows*cellHeight)
+	width, height := small.Bounds().Dx(), small.Bounds().Dy()
+	if width == 0 || height == 0 {
+		return ""
+	}
+
+	level := func(v uint8) int { return (int(v)*5 + 127) / 255 }
+
+	pixels := make([]int, width*height)
+	used := make([]bool, 216)
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			c := small.RGBAAt(x, y)
+			index := level(c.R)*36 + level(c.G)*6 + level(c.B)
+			pixels[y*width+x] = index
+			used[index] = true
+		}
+	}
+
+	var out strings.Builder
+	fmt.Fprintf(&out, "\x1bP0;1;0q\"1;1;%d;%d", width, height)
+
+	for index, ok := range used {
+		if ok {
+			fmt.Fprintf(&out, "#%d;2;%d;%d;%d", index, index/36*20, index/6%6*20, index%6*20)
+		}
+	}
+
+	// Every band covers 6 pixel rows, each color is drawn over it in turn
+	for band := 0; band < height; band += 6 {
+		for index, ok := range used {
+			if !ok {
+				continue
+			}
+
+			var line strings.Builder
+			found := false
+			last, run := byte(0), 0
+
+			flush := func() {
+				switch {
+				case run > 3:
+					fmt.Fprintf(&line, "!%d%c", run, last)
+				case run > 0:
+					line.WriteString(strings.Repeat(string(last), run))
+				}
+			}
+
+			for x := 0; x < width; x++ {
+				bits := 0
+				for dy := 0; dy < 6 && band+dy < height; dy++ {
+					if pixels[(band+dy)*width+x] == index {
+						bits |= 1 << dy
+					}
+				}
+				if bits != 0 {
+					found = true
+				}
+
+				char := byte(63 + bits)
+				if char == last {
+					run++
+					continue
+				}
+
+				flush()
+				last, run = char, 1
+			}
+			flush()
+
+			if found {
+				fmt.Fprintf(&out, "#%d%s$", index, line.String())
+			}
+		}
+
+		out.WriteString("-")
+	}
+
+	out.WriteString("\x1b\\")
+	return out.String()
+}
@@ -6,7 +6,9 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"sync"
 	"syscall"
 	"time"
 
@@ -19,6 +21,21 @@ var (
 	mpvCmd    *exec.Cmd
 )
 
+// Audio settings as last sent to mpv, changing them rebuilds the filter chain so they are only sent when they differ
+var (
+	audioMu        sync.Mutex
+	replayGainMode string
+	loudnormActive bool
+)
+
+type AudioLevels struct {
+	Left  float64
+	Right float64
+}
+
+// Floor of the level meter (in dBFS)
+const SilenceLevel = -60.0
+
 type PlayerStatus struct {
 	Title    string
 	Artist   string
@@ -27,11 +44,10 @@ type PlayerStatus struct {
 	Duration float64
 	Paused   bool
 	Volume   float64
+	Muted    bool
 	Path     string
 }
 
-const volumeStep = 5
-
 func InitPlayer() error {
 	socketPath := filepath.Join(os.TempDir(), fmt.Sprintf("subtui_mpv_socket_%d", os.Getuid()))
 	log.Printf("[Player] Initializing MPV IPC at %s", socketPath)
@@ -40,10 +56,29 @@ func InitPlayer() error {
 	_ = exec.Command("pkill", "-f", "--", killArg).Run()
 
 	replayGain := strings.ToLower(api.AppConfig.App.ReplayGain)
-	if replayGain != "track" && replayGain != "album" {
+	if replayGain == "auto" {
+		replayGain = "track"
+	} else if replayGain != "track" && replayGain != "album" {
 		replayGain = "no"
 	}
 
+	replayGainMode = replayGain
+
+	replayGainClip := "no"
+	if api.AppConfig.App.ReplayGainClip {
+		replayGainClip = "yes"
+	}
+
+	volume := api.AppState.Player.Volume
+	if volume < 0 || volume > volumeMax() {
+		volume = 100
+	}
+
+	mute := "no"
+	if api.AppState.Player.Muted {
+		mute = "yes"
+	}
+
 	args := []string{
 		"--idle",
 		"--no-video",
@@ -51,6 +86,11 @@ func InitPlayer() error {
 		"--gapless-audio=yes",
 		"--prefetch-playlist=yes",
 		"--replaygain=" + replayGain,
+		"--replaygain-preamp=" + strconv.FormatFloat(api.AppConfig.App.ReplayGainPreamp, 'f', -1, 64),
+		"--replaygain-clip=" + replayGainClip,
+		"--volume-max=" + strconv.Itoa(volumeMax()),
+		"--volume=" + strconv.Itoa(volume),
+		"--mute=" + mute,
 	}
 
 	mpvCmd = exec.Command("mpv", args...)
@@ -150,20 +190,58 @@ func Forward10Seconds() {
 	_ = mpvClient.Seek(+10)
 }
 
+// Helper: Volume step from config, falls back to 5%
+func volumeStep() int {
+	if api.AppConfig.App.VolumeStep <= 0 {
+		return 5
+	}
+
+	return api.AppConfig.App.VolumeStep
+}
+
+// Helper: Maximum volume from config, MPV accepts 100 up to 1000
+func volumeMax() int {
+	maxVolume := api.AppConfig.App.VolumeMax
+	if maxVolume < 100 {
+		return 100
+	}
+	if maxVolume > 1000 {
+		return 1000
+	}
+
+	return maxVolume
+}
+
 func VolumeUp() {
-	if mpvClient.CurrentVolume()+volumeStep > 100 {
-		_ = mpvClient.Volume(100)
+	if mpvClient.CurrentVolume()+volumeStep() > volumeMax() {
+		_ = mpvClient.Volume(volumeMax())
 		return
 	}
-	_ = mpvClient.Volume(mpvClient.CurrentVolume() + volumeStep)
+	_ = mpvClient.Volume(mpvClient.CurrentVolume() + volumeStep())
 }
 
 func VolumeDown() {
-	if mpvClient.CurrentVolume()-volumeStep < 0 {
+	if mpvClient.CurrentVolume()-volumeStep() < 0 {
 		_ = mpvClient.Volume(0)
 		return
 	}
-	_ = mpvClient.Volume(mpvClient.CurrentVolume() - volumeStep)
+	_ = mpvClient.Volume(mpvClient.CurrentVolume() - volumeStep())
+}
+
+func ToggleMute() {
+	if mpvClient == nil {
+		return
+	}
+
+	_ = mpvClient.Mute()
+}
+
+func IsMuted() bool {
+	if mpvClient == nil {
+		return false
+	}
+
+	return mpvClient.IsMute()
 }
 
 func GetVolume() float64 {
@@ -175,6 +253,105 @@ func SetVolume(volume int) {
 	_ = mpvClient.Volume(volume)
 }
 
+func SetReplayGain(mode string) {
+	if mpvClient == nil {
+		return
+	}
+
+	audioMu.Lock()
+	defer audioMu.Unlock()
+
+	if mode == replayGainMode {
+		return
+	}
+
+	if err := mpvClient.SetProperty("replaygain", mode); err == nil {
+		replayGainMode = mode
+	}
+}
+
+func SetLoudnessNormalization(enabled bool) {
+	if mpvClient == nil {
+		return
+	}
+
+	filter := ""
+	switch strings.ToLower(api.AppConfig.App.LoudnessNormalization) {
+	case "loudnorm":
+		filter = "lavfi=[loudnorm=I=-16:TP=-1.5:LRA=11]"
+	case "dynaudnorm":
+		filter = "lavfi=[dynaudnorm]"
+	default:
+		enabled = false
+	}
+
+	audioMu.Lock()
+	defer audioMu.Unlock()
+
+	if enabled == loudnormActive {
+		return
+	}
+
+	// Labeled so other audio filters are left untouched
+	if enabled {
+		_, _ = mpvClient.Exec("af", "add", "@subtui-loudnorm:"+filter)
+	} else {
+		_, _ = mpvClient.Exec("af", "remove", "@subtui-loudnorm")
+	}
+	loudnormActive = enabled
+}
+
+func SetLevelMeter(enabled bool) {
+	if mpvClient == nil {
+		return
+	}
+
+	_, _ = mpvClient.Exec("af", "remove", "@subtui-levels")
+	if enabled {
+		_, _ = mpvClient.Exec("af", "add", "@subtui-levels:lavfi=[astats=metadata=1:reset=1]")
+	}
+}
+
+func GetAudioLevels() AudioLevels {
+	levels := AudioLevels{Left: SilenceLevel, Right: SilenceLevel}
+	if mpvClient == nil {
+		return levels
+	}
+
+	res, err := mpvClient.Exec("get_property", "af-metadata/subtui-levels")
+	if err != nil || res == nil {
+		return levels
+	}
+
+	data, ok := res.Data.(map[string]interface{})
+	if !ok {
+		return levels
+	}
+
+	levels.Left = parseLevel(data["lavfi.astats.1.RMS_level"])
+	levels.Right = levels.Left
+	if _, isStereo := data["lavfi.astats.2.RMS_level"]; isStereo {
+		levels.Right = parseLevel(data["lavfi.astats.2.RMS_level"])
+	}
+
+	return levels
+}
+
+// Helper: Parse an astats level, silence is reported as '-inf'
+func parseLevel(value interface{}) float64 {
+	str, ok := value.(string)
+	if !ok {
+		return SilenceLevel
+	}
+
+	level, err := strconv.ParseFloat(str, 64)
+	if err != nil || level < SilenceLevel {
+		return SilenceLevel
+	}
+
+	return level
+}
+
 func GetPlayerStatus() PlayerStatus {
 	if mpvClient == nil {
 		return PlayerStatus{}
@@ -188,6 +365,7 @@ func GetPlayerStatus() PlayerStatus {
 	dur := mpvClient.Duration()
 	paused := mpvClient.IsPause()
 	vol, _ := mpvClient.GetFloatProperty("volume")
+	muted := mpvClient.IsMute()
 
 	path := mpvClient.GetProperty("path")
 
@@ -199,6 +377,7 @@ func GetPlayerStatus() PlayerStatus {
 		Duration: dur,
 		Paused:   paused,
 		Volume:   vol,
+		Muted:    muted,
 		Path:     fmt.Sprintf("%v", path),
 	}
 }
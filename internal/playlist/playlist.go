@@ -0,0 +1,305 @@
+package playlist
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/MattiaPun/SubTUI/v2/internal/api"
+)
+
+const (
+	FormatM3U8 = "m3u8"
+	FormatXSPF = "xspf"
+	FormatJSON = "json"
+)
+
+type Entry struct {
+	ID       string `json:"id,omitempty"`
+	Title    string `json:"title"`
+	Artist   string `json:"artist"`
+	Album    string `json:"album"`
+	Duration int    `json:"duration"`
+	Path     string `json:"path,omitempty"`
+}
+
+type jsonPlaylist struct {
+	Name  string  `json:"name"`
+	Songs []Entry `json:"songs"`
+}
+
+type xspfPlaylist struct {
+	XMLName xml.Name    `xml:"playlist"`
+	Version string      `xml:"version,attr"`
+	Xmlns   string      `xml:"xmlns,attr"`
+	Title   string      `xml:"title,omitempty"`
+	Tracks  []xspfTrack `xml:"trackList>track"`
+}
+
+type xspfTrack struct {
+	Location string `xml:"location,omitempty"`
+	Title    string `xml:"title,omitempty"`
+	Creator  string `xml:"creator,omitempty"`
+	Album    string `xml:"album,omitempty"`
+	Duration int    `xml:"duration,omitempty"`
+}
+
+// Helper: Playlist format from the file extension
+func FormatFromPath(path string) (string, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".m3u8", ".m3u":
+		return FormatM3U8, nil
+	case ".xspf":
+		return FormatXSPF, nil
+	case ".json":
+		return FormatJSON, nil
+	}
+
+	return "", fmt.Errorf("unsupported playlist format %q, use .m3u8, .xspf or .json", filepath.Ext(path))
+}
+
+// Helper: Expand a leading ~ to the home directory
+func ExpandPath(path string) string {
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, strings.TrimPrefix(path, "~"))
+		}
+	}
+
+	return path
+}
+
+func Export(path string, name string, songs []api.Song) error {
+	format, err := FormatFromPath(path)
+	if err != nil {
+		return err
+	}
+
+	var data []byte
+	switch format {
+	case FormatM3U8:
+		data = encodeM3U8(songs)
+	case FormatXSPF:
+		data, err = encodeXSPF(name, songs)
+	case FormatJSON:
+		data, err = encodeJSON(name, songs)
+	}
+
+	if err != nil {
+		return fmt.Errorf("could not encode playlist: %v", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, data, 0644)
+}
+
+func Import(path string) ([]Entry, error) {
+	format, err := FormatFromPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not open playlist: %v", err)
+	}
+
+	switch format {
+	case FormatXSPF:
+		return decodeXSPF(data)
+	case FormatJSON:
+		return decodeJSON(data)
+	}
+
+	return decodeM3U8(data), nil
+}
+
+// Helper: Where an exported song points to, the server path or a stream URL.
+// Stream URLs carry no credentials, players need to log in themselves.
+func location(song api.Song) string {
+	if strings.ToLower(api.AppConfig.App.ExportLocation) == "stream" || song.Path == "" {
+		return api.SubsonicStreamLocation(song.ID)
+	}
+
+	return song.Path
+}
+
+// Helper: XSPF locations are URIs, server paths become percent-encoded file: URIs.
+// Relative paths stay relative, as file:Artist/Album/01%20Song.flac
+func uriLocation(song api.Song) string {
+	location := location(song)
+	if location != song.Path {
+		return location
+	}
+
+	escaped := (&url.URL{Path: location}).EscapedPath()
+	if strings.HasPrefix(location, "/") {
+		return "file://" + escaped
+	}
+
+	return "file:" + escaped
+}
+
+func encodeM3U8(songs []api.Song) []byte {
+	var b strings.Builder
+
+	b.WriteString("#EXTM3U\n")
+	for _, song := range songs {
+		fmt.Fprintf(&b, "#EXTINF:%d,%s - %s\n", song.Duration, song.Artist, song.Title)
+		if song.Album != "" {
+			fmt.Fprintf(&b, "#EXTALB:%s\n", song.Album)
+		}
+		b.WriteString(location(song) + "\n")
+	}
+
+	return []byte(b.String())
+}
+
+func decodeM3U8(data []byte) []Entry {
+	var entries []Entry
+	var current Entry
+
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(strings.TrimPrefix(line, "\ufeff"))
+
+		switch {
+		case line == "" || line == "#EXTM3U":
+			continue
+
+		case strings.HasPrefix(line, "#EXTINF:"):
+			info := strings.TrimPrefix(line, "#EXTINF:")
+			duration, title, _ := strings.Cut(info, ",")
+			current.Duration, _ = strconv.Atoi(strings.TrimSpace(duration))
+
+			if artist, songTitle, found := strings.Cut(title, " - "); found {
+				current.Artist = strings.TrimSpace(artist)
+				current.Title = strings.TrimSpace(songTitle)
+			} else {
+				current.Title = strings.TrimSpace(title)
+			}
+
+		case strings.HasPrefix(line, "#EXTALB:"):
+			current.Album = strings.TrimSpace(strings.TrimPrefix(line, "#EXTALB:"))
+
+		case strings.HasPrefix(line, "#"):
+			continue
+
+		default:
+			current.ID, current.Path = parseLocation(line)
+			entries = append(entries, current)
+			current = Entry{}
+		}
+	}
+
+	return entries
+}
+
+func encodeXSPF(name string, songs []api.Song) ([]byte, error) {
+	playlist := xspfPlaylist{
+		Version: "1",
+		Xmlns:   "http://xspf.org/ns/0/",
+		Title:   name,
+	}
+
+	for _, song := range songs {
+		playlist.Tracks = append(playlist.Tracks, xspfTrack{
+			Location: uriLocation(song),
+			Title:    song.Title,
+			Creator:  song.Artist,
+			Album:    song.Album,
+			Duration: song.Duration * 1000,
+		})
+	}
+
+	data, err := xml.MarshalIndent(playlist, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]byte(xml.Header), append(data, '\n')...), nil
+}
+
+func decodeXSPF(data []byte) ([]Entry, error) {
+	var playlist xspfPlaylist
+	if err := xml.Unmarshal(data, &playlist); err != nil {
+		return nil, fmt.Errorf("could not decode xspf: %v", err)
+	}
+
+	entries := make([]Entry, 0, len(playlist.Tracks))
+	for _, track := range playlist.Tracks {
+		id, path := parseLocation(track.Location)
+		entries = append(entries, Entry{
+			ID:       id,
+			Title:    track.Title,
+			Artist:   track.Creator,
+			Album:    track.Album,
+			Duration: track.Duration / 1000,
+			Path:     path,
+		})
+	}
+
+	return entries, nil
+}
+
+func encodeJSON(name string, songs []api.Song) ([]byte, error) {
+	playlist := jsonPlaylist{Name: name, Songs: []Entry{}}
+
+	for _, song := range songs {
+		playlist.Songs = append(playlist.Songs, Entry{
+			ID:       song.ID,
+			Title:    song.Title,
+			Artist:   song.Artist,
+			Album:    song.Album,
+			Duration: song.Duration,
+			Path:     song.Path,
+		})
+	}
+
+	data, err := json.MarshalIndent(playlist, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return append(data, '\n'), nil
+}
+
+func decodeJSON(data []byte) ([]Entry, error) {
+	var playlist jsonPlaylist
+	if err := json.Unmarshal(data, &playlist); err != nil {
+		return nil, fmt.Errorf("could not decode json: %v", err)
+	}
+
+	return playlist.Songs, nil
+}
+
+// Helper: Split a location into a server song ID (for stream URLs) or a file path
+func parseLocation(location string) (string, string) {
+	if strings.HasPrefix(location, "http://") || strings.HasPrefix(location, "https://") || strings.Contains(location, "/rest/stream?") {
+		if parsed, err := url.Parse(location); err == nil {
+			return parsed.Query().Get("id"), ""
+		}
+
+		return "", ""
+	}
+
+	if parsed, err := url.Parse(location); err == nil && parsed.Scheme == "file" {
+		// Relative file: URIs keep their path percent-encoded
+		if parsed.Opaque != "" {
+			if path, err := url.PathUnescape(parsed.Opaque); err == nil {
+				return "", path
+			}
+		}
+
+		return "", parsed.Path
+	}
+
+	return "", location
+}
@@ -0,0 +1,108 @@
+package playlist
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/MattiaPun/SubTUI/v2/internal/api"
+)
+
+// Resolve matches entries to server songs by ID, path or title, artist and album
+func Resolve(entries []Entry) ([]api.Song, []Entry) {
+	var songs []api.Song
+	var unmatched []Entry
+
+	for _, entry := range entries {
+		if song, ok := resolveEntry(entry); ok {
+			songs = append(songs, song)
+		} else {
+			unmatched = append(unmatched, entry)
+		}
+	}
+
+	return songs, unmatched
+}
+
+func resolveEntry(entry Entry) (api.Song, bool) {
+	if entry.ID != "" {
+		song, err := api.SubsonicGetSong(entry.ID)
+		if err == nil && song.ID != "" {
+			return song, true
+		}
+	}
+
+	candidates, err := api.SubsonicSearchSong(searchQuery(entry), 0)
+	if err != nil || len(candidates) == 0 {
+		return api.Song{}, false
+	}
+
+	if entry.Path != "" {
+		for _, song := range candidates {
+			if pathMatches(entry.Path, song.Path) {
+				return song, true
+			}
+		}
+	}
+
+	if entry.Title == "" {
+		return api.Song{}, false
+	}
+
+	// Prefer a match on the album, fall back to title and artist only
+	var fallback *api.Song
+	for i, song := range candidates {
+		if !strings.EqualFold(song.Title, entry.Title) {
+			continue
+		}
+
+		if entry.Artist != "" && !strings.EqualFold(song.Artist, entry.Artist) {
+			continue
+		}
+
+		if entry.Album == "" || strings.EqualFold(song.Album, entry.Album) {
+			return song, true
+		}
+
+		if fallback == nil {
+			fallback = &candidates[i]
+		}
+	}
+
+	if fallback != nil {
+		return *fallback, true
+	}
+
+	return api.Song{}, false
+}
+
+// Helper: Search query for an entry, the title or the file name
+func searchQuery(entry Entry) string {
+	if entry.Title != "" {
+		return entry.Title
+	}
+
+	name := strings.TrimSuffix(filepath.Base(entry.Path), filepath.Ext(entry.Path))
+
+	// Drop a leading track number like "01 - "
+	if number, rest, found := strings.Cut(name, " - "); found && strings.Trim(number, "0123456789") == "" {
+		name = rest
+	}
+
+	return name
+}
+
+// Helper: Check if two paths point to the same file, ignoring the library root
+func pathMatches(a string, b string) bool {
+	if a == "" || b == "" {
+		return false
+	}
+
+	a = filepath.ToSlash(a)
+	b = filepath.ToSlash(b)
+
+	if len(a) < len(b) {
+		a, b = b, a
+	}
+
+	return a == b || strings.HasSuffix(a, "/"+strings.TrimPrefix(b, "/"))
+}
@@ -0,0 +1,223 @@
+package playlist
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/MattiaPun/SubTUI/v2/internal/api"
+)
+
+type SyncPlan struct {
+	Name       string
+	PlaylistID string // Empty when the playlist has to be created
+	Songs      []api.Song
+	Unmatched  []Entry
+	Added      []api.Song
+	Removed    []api.Song
+	Reordered  bool
+	Duplicates int // Server playlists sharing the name, the plan is skipped when more than one
+	serverSize int
+}
+
+// Helper: Check if applying the plan changes the server
+func (p SyncPlan) Changed() bool {
+	if p.Duplicates > 1 {
+		return false
+	}
+
+	return p.PlaylistID == "" || len(p.Added) > 0 || len(p.Removed) > 0 || p.Reordered
+}
+
+// Diff of the plan, one line per change
+func (p SyncPlan) Summary() []string {
+	var lines []string
+
+	switch {
+	case p.Duplicates > 1:
+		return []string{fmt.Sprintf("! %s (skipped, %d server playlists share this name)", p.Name, p.Duplicates)}
+	case p.PlaylistID == "":
+		lines = append(lines, fmt.Sprintf("+ %s (new, %d songs)", p.Name, len(p.Songs)))
+	case p.Changed():
+		lines = append(lines, fmt.Sprintf("~ %s (+%d -%d)", p.Name, len(p.Added), len(p.Removed)))
+	default:
+		lines = append(lines, fmt.Sprintf("= %s (unchanged)", p.Name))
+	}
+
+	for _, song := range p.Added {
+		lines = append(lines, fmt.Sprintf("    + %s - %s", song.Artist, song.Title))
+	}
+
+	for _, song := range p.Removed {
+		lines = append(lines, fmt.Sprintf("    - %s - %s", song.Artist, song.Title))
+	}
+
+	if p.Reordered {
+		lines = append(lines, "    ~ order changed")
+	}
+
+	for _, entry := range p.Unmatched {
+		lines = append(lines, fmt.Sprintf("    ! not found: %s", entry.Path))
+	}
+
+	return lines
+}
+
+// PlanSync compares the M3U files in dir with the server playlists of the same name
+func PlanSync(dir string) ([]SyncPlan, error) {
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("could not read playlist dir: %v", err)
+	}
+
+	playlists, err := api.SubsonicGetPlaylists()
+	if err != nil {
+		return nil, err
+	}
+
+	serverIDs := make(map[string][]string)
+	for _, playlist := range playlists {
+		serverIDs[playlist.Name] = append(serverIDs[playlist.Name], playlist.ID)
+	}
+
+	var plans []SyncPlan
+	for _, file := range files {
+		ext := strings.ToLower(filepath.Ext(file.Name()))
+		if file.IsDir() || (ext != ".m3u" && ext != ".m3u8") {
+			continue
+		}
+
+		entries, err := Import(filepath.Join(dir, file.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		name := strings.TrimSuffix(file.Name(), filepath.Ext(file.Name()))
+		plan := SyncPlan{Name: name, Duplicates: len(serverIDs[name])}
+		if plan.Duplicates > 1 {
+			plans = append(plans, plan)
+			continue
+		}
+
+		if plan.Duplicates == 1 {
+			plan.PlaylistID = serverIDs[name][0]
+		}
+
+		for _, entry := range entries {
+			if song, ok := resolvePath(entry); ok {
+				plan.Songs = append(plan.Songs, song)
+			} else {
+				plan.Unmatched = append(plan.Unmatched, entry)
+			}
+		}
+
+		if plan.PlaylistID != "" {
+			serverSongs, err := api.SubsonicGetPlaylistSongs(plan.PlaylistID)
+			if err != nil {
+				return nil, err
+			}
+
+			plan.serverSize = len(serverSongs)
+			plan.Added, plan.Removed, plan.Reordered = diffSongs(serverSongs, plan.Songs)
+		}
+
+		plans = append(plans, plan)
+	}
+
+	sort.Slice(plans, func(i, j int) bool {
+		return plans[i].Name < plans[j].Name
+	})
+
+	return plans, nil
+}
+
+// ApplySync creates or updates the server playlists of the changed plans
+func ApplySync(plans []SyncPlan) error {
+	for _, plan := range plans {
+		if !plan.Changed() {
+			continue
+		}
+
+		ids := make([]string, 0, len(plan.Songs))
+		for _, song := range plan.Songs {
+			ids = append(ids, song.ID)
+		}
+
+		var err error
+		if plan.PlaylistID == "" {
+			err = api.SubsonicCreatePlaylist(plan.Name, ids)
+		} else {
+			err = api.SubsonicReplacePlaylistSongs(plan.PlaylistID, plan.serverSize, ids)
+		}
+
+		if err != nil {
+			return fmt.Errorf("%s: %v", plan.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// Helper: Match an entry to a server song by the suffix of its path
+func resolvePath(entry Entry) (api.Song, bool) {
+	if entry.Path == "" {
+		return api.Song{}, false
+	}
+
+	queries := []string{searchQuery(entry)}
+	if entry.Title != "" {
+		queries = append(queries, searchQuery(Entry{Path: entry.Path}))
+	}
+
+	for _, query := range queries {
+		candidates, err := api.SubsonicSearchSong(query, 0)
+		if err != nil {
+			continue
+		}
+
+		for _, song := range candidates {
+			if pathMatches(entry.Path, song.Path) {
+				return song, true
+			}
+		}
+	}
+
+	return api.Song{}, false
+}
+
+// Helper: Songs added and removed between two lists, and if the shared songs moved
+func diffSongs(current []api.Song, target []api.Song) ([]api.Song, []api.Song, bool) {
+	var added, removed []api.Song
+
+	currentIDs := make(map[string]bool)
+	for _, song := range current {
+		currentIDs[song.ID] = true
+	}
+
+	targetIDs := make(map[string]bool)
+	for _, song := range target {
+		targetIDs[song.ID] = true
+		if !currentIDs[song.ID] {
+			added = append(added, song)
+		}
+	}
+
+	var currentOrder, targetOrder []string
+	for _, song := range current {
+		if !targetIDs[song.ID] {
+			removed = append(removed, song)
+		} else {
+			currentOrder = append(currentOrder, song.ID)
+		}
+	}
+
+	for _, song := range target {
+		if currentIDs[song.ID] {
+			targetOrder = append(targetOrder, song.ID)
+		}
+	}
+
+	return added, removed, strings.Join(currentOrder, ",") != strings.Join(targetOrder, ",")
+}
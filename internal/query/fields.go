@@ -0,0 +1,66 @@
+package query
+
+import "github.com/MattiaPun/SubTUI/v2/internal/api"
+
+func SongFields(song api.Song, starred bool) Fields {
+	albumArtists := make([]string, 0, len(song.AlbumArtists))
+	for _, artist := range song.AlbumArtists {
+		albumArtists = append(albumArtists, artist.Name)
+	}
+
+	return Fields{
+		Text: map[string][]string{
+			"title":       {song.Title},
+			"artist":      {song.Artist},
+			"album":       {song.Album},
+			"albumartist": albumArtists,
+			"genre":       {song.Genre},
+			"path":        {song.Path},
+			"note":        {song.Note},
+		},
+		Numbers: map[string]int{
+			"year":     song.Year,
+			"rating":   song.Rating,
+			"played":   song.PlayCount,
+			"duration": song.Duration,
+			"track":    song.TrackNumber,
+			"disc":     song.DiscNumber,
+			"starred":  boolNumber(starred),
+		},
+	}
+}
+
+func AlbumFields(album api.Album, starred bool) Fields {
+	return Fields{
+		Text: map[string][]string{
+			"album":       {album.Name},
+			"artist":      {album.Artist},
+			"albumartist": {album.Artist},
+		},
+		Numbers: map[string]int{
+			"rating":   album.Rating,
+			"duration": int(album.Duration),
+			"starred":  boolNumber(starred),
+		},
+	}
+}
+
+func ArtistFields(artist api.Artist, starred bool) Fields {
+	return Fields{
+		Text: map[string][]string{
+			"artist": {artist.Name},
+		},
+		Numbers: map[string]int{
+			"rating":  artist.Rating,
+			"starred": boolNumber(starred),
+		},
+	}
+}
+
+func boolNumber(b bool) int {
+	if b {
+		return 1
+	}
+
+	return 0
+}
@@ -0,0 +1,148 @@
+package query
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	tokTerm = iota
+	tokLParen
+	tokRParen
+	tokAnd
+	tokOr
+	tokNot
+)
+
+type token struct {
+	kind  int
+	field string // Empty for a bare word
+	op    string
+	value string
+	regex bool
+}
+
+var operators = []string{">=", "<=", "!=", ":", "=", ">", "<", "~"}
+
+func lex(input string) ([]token, error) {
+	var tokens []token
+	runes := []rune(input)
+
+	for i := 0; i < len(runes); {
+		c := runes[i]
+
+		switch {
+		case c == ' ' || c == '\t':
+			i++
+
+		case c == '(':
+			tokens = append(tokens, token{kind: tokLParen})
+			i++
+
+		case c == ')':
+			tokens = append(tokens, token{kind: tokRParen})
+			i++
+
+		case c == '|':
+			tokens = append(tokens, token{kind: tokOr})
+			i++
+
+		case c == '&':
+			tokens = append(tokens, token{kind: tokAnd})
+			i++
+
+		case (c == '-' || c == '!') && i+1 < len(runes) && runes[i+1] != ' ':
+			tokens = append(tokens, token{kind: tokNot})
+			i++
+
+		default:
+			tok, next, err := lexTerm(runes, i)
+			if err != nil {
+				return nil, err
+			}
+
+			tokens = append(tokens, tok)
+			i = next
+		}
+	}
+
+	return tokens, nil
+}
+
+// Helper: Read a field:value term or a bare word starting at i
+func lexTerm(runes []rune, i int) (token, int, error) {
+	start := i
+	for i < len(runes) && isFieldRune(runes[i]) {
+		i++
+	}
+
+	field := strings.ToLower(string(runes[start:i]))
+
+	if field != "" {
+		rest := string(runes[i:])
+		for _, op := range operators {
+			if strings.HasPrefix(rest, op) {
+				value, regex, next, err := lexValue(runes, i+len([]rune(op)))
+				if err != nil {
+					return token{}, 0, err
+				}
+
+				return token{kind: tokTerm, field: field, op: op, value: value, regex: regex}, next, nil
+			}
+		}
+	}
+
+	value, regex, next, err := lexValue(runes, start)
+	if err != nil {
+		return token{}, 0, err
+	}
+
+	// Keywords only count when they are not quoted
+	if !regex && next-start == len([]rune(value)) {
+		switch value {
+		case "AND":
+			return token{kind: tokAnd}, next, nil
+		case "OR":
+			return token{kind: tokOr}, next, nil
+		case "NOT":
+			return token{kind: tokNot}, next, nil
+		}
+	}
+
+	return token{kind: tokTerm, op: ":", value: value, regex: regex}, next, nil
+}
+
+// Helper: Read a quoted, /regex/ or bare value starting at i, bare values end at spaces and operators
+func lexValue(runes []rune, i int) (string, bool, int, error) {
+	if i < len(runes) && (runes[i] == '"' || runes[i] == '/') {
+		quote := runes[i]
+		var value []rune
+
+		for j := i + 1; j < len(runes); j++ {
+			if runes[j] == '\\' && j+1 < len(runes) && runes[j+1] == quote {
+				value = append(value, quote)
+				j++
+				continue
+			}
+
+			if runes[j] == quote {
+				return string(value), quote == '/', j + 1, nil
+			}
+
+			value = append(value, runes[j])
+		}
+
+		return "", false, 0, fmt.Errorf("missing closing %c", quote)
+	}
+
+	start := i
+	for i < len(runes) && !strings.ContainsRune(" \t()|&", runes[i]) {
+		i++
+	}
+
+	return string(runes[start:i]), false, i, nil
+}
+
+func isFieldRune(r rune) bool {
+	return (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || r == '_'
+}
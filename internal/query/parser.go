@@ -0,0 +1,135 @@
+package query
+
+import (
+	"fmt"
+	"strings"
+)
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+// Parse compiles a query, an empty query returns a nil Expr
+func Parse(input string) (Expr, error) {
+	if strings.TrimSpace(input) == "" {
+		return nil, nil
+	}
+
+	tokens, err := lex(input)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens}
+	expr, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("unexpected )")
+	}
+
+	return expr, nil
+}
+
+func (p *parser) peek() (token, bool) {
+	if p.pos >= len(p.tokens) {
+		return token{}, false
+	}
+
+	return p.tokens[p.pos], true
+}
+
+func (p *parser) parseOr() (Expr, error) {
+	var exprs []Expr
+
+	for {
+		expr, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		exprs = append(exprs, expr)
+
+		tok, ok := p.peek()
+		if !ok || tok.kind != tokOr {
+			break
+		}
+		p.pos++
+	}
+
+	if len(exprs) == 1 {
+		return exprs[0], nil
+	}
+
+	return orExpr(exprs), nil
+}
+
+// Terms next to each other are joined with AND
+func (p *parser) parseAnd() (Expr, error) {
+	var exprs []Expr
+
+	for {
+		tok, ok := p.peek()
+		if !ok || tok.kind == tokOr || tok.kind == tokRParen {
+			break
+		}
+
+		if tok.kind == tokAnd {
+			p.pos++
+			continue
+		}
+
+		expr, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		exprs = append(exprs, expr)
+	}
+
+	switch len(exprs) {
+	case 0:
+		return nil, fmt.Errorf("expected a term")
+	case 1:
+		return exprs[0], nil
+	}
+
+	return andExpr(exprs), nil
+}
+
+func (p *parser) parseUnary() (Expr, error) {
+	tok, ok := p.peek()
+	if !ok {
+		return nil, fmt.Errorf("expected a term")
+	}
+
+	switch tok.kind {
+	case tokNot:
+		p.pos++
+		expr, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return notExpr{expr}, nil
+
+	case tokLParen:
+		p.pos++
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+
+		if next, ok := p.peek(); !ok || next.kind != tokRParen {
+			return nil, fmt.Errorf("missing closing )")
+		}
+		p.pos++
+		return expr, nil
+
+	case tokTerm:
+		p.pos++
+		return newTerm(tok)
+	}
+
+	return nil, fmt.Errorf("expected a term")
+}
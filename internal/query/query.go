@@ -0,0 +1,278 @@
+// Package query implements the filter language used for exclusion rules and live filters.
+//
+//	genre:jazz year:1950..1969 rating>=4 -artist:"Kenny G" played<3
+//	(artist:/beatles?/ | artist=Wings) AND NOT starred:true
+package query
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Fields holds the values of a record that a query is matched against
+type Fields struct {
+	Text    map[string][]string
+	Numbers map[string]int
+}
+
+type Expr interface {
+	Match(fields Fields) bool
+}
+
+type orExpr []Expr
+type andExpr []Expr
+type notExpr struct{ expr Expr }
+
+type textExpr struct {
+	field string
+	op    string
+	value string
+	regex *regexp.Regexp
+}
+
+type numberExpr struct {
+	field    string
+	op       string
+	min, max int
+	hasMin   bool
+	hasMax   bool
+}
+
+// Bare words match any of these fields
+var bareFields = []string{"title", "artist", "album"}
+
+var textFields = map[string]string{
+	"title":        "title",
+	"artist":       "artist",
+	"album":        "album",
+	"albumartist":  "albumartist",
+	"album_artist": "albumartist",
+	"genre":        "genre",
+	"path":         "path",
+	"note":         "note",
+	"comment":      "note",
+}
+
+var numberFields = map[string]string{
+	"year":      "year",
+	"rating":    "rating",
+	"played":    "played",
+	"plays":     "played",
+	"playcount": "played",
+	"duration":  "duration",
+	"track":     "track",
+	"disc":      "disc",
+	"starred":   "starred",
+	"favorite":  "starred",
+}
+
+func (e orExpr) Match(fields Fields) bool {
+	for _, expr := range e {
+		if expr.Match(fields) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (e andExpr) Match(fields Fields) bool {
+	for _, expr := range e {
+		if !expr.Match(fields) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (e notExpr) Match(fields Fields) bool {
+	return !e.expr.Match(fields)
+}
+
+func (e textExpr) Match(fields Fields) bool {
+	values := fields.Text[e.field]
+	if e.field == "" {
+		for _, field := range bareFields {
+			values = append(values, fields.Text[field]...)
+		}
+	}
+
+	matched := false
+	for _, value := range values {
+		switch {
+		case e.regex != nil:
+			matched = e.regex.MatchString(value)
+		case e.op == "=" || e.op == "!=":
+			matched = strings.EqualFold(value, e.value)
+		default:
+			matched = strings.Contains(strings.ToLower(value), strings.ToLower(e.value))
+		}
+
+		if matched {
+			break
+		}
+	}
+
+	if e.op == "!=" {
+		return !matched
+	}
+
+	return matched
+}
+
+func (e numberExpr) Match(fields Fields) bool {
+	value, ok := fields.Numbers[e.field]
+	if !ok {
+		return false
+	}
+
+	switch e.op {
+	case ">":
+		return value > e.min
+	case ">=":
+		return value >= e.min
+	case "<":
+		return value < e.max
+	case "<=":
+		return value <= e.max
+	case "!=":
+		return value != e.min
+	}
+
+	// Equality or a range
+	return (!e.hasMin || value >= e.min) && (!e.hasMax || value <= e.max)
+}
+
+func Or(exprs ...Expr) Expr {
+	return orExpr(exprs)
+}
+
+func And(exprs ...Expr) Expr {
+	return andExpr(exprs)
+}
+
+func Not(expr Expr) Expr {
+	return notExpr{expr}
+}
+
+// Term builds a single field comparison, like the ones typed as field:value
+func Term(field string, op string, value string) (Expr, error) {
+	return newTerm(token{kind: tokTerm, field: strings.ToLower(field), op: op, value: value})
+}
+
+func newTerm(tok token) (Expr, error) {
+	if tok.field == "" {
+		return newText("", tok)
+	}
+
+	if field, ok := textFields[tok.field]; ok {
+		return newText(field, tok)
+	}
+
+	if field, ok := numberFields[tok.field]; ok {
+		return newNumber(field, tok)
+	}
+
+	return nil, fmt.Errorf("unknown field %q", tok.field)
+}
+
+func newText(field string, tok token) (Expr, error) {
+	expr := textExpr{field: field, op: tok.op, value: tok.value}
+
+	switch tok.op {
+	case ":", "=", "!=":
+	case "~":
+		tok.regex = true
+	default:
+		return nil, fmt.Errorf("%s can't be compared with %s", displayField(field), tok.op)
+	}
+
+	if tok.regex {
+		regex, err := regexp.Compile("(?i)" + tok.value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid regex %q", tok.value)
+		}
+		expr.regex = regex
+	}
+
+	return expr, nil
+}
+
+func newNumber(field string, tok token) (Expr, error) {
+	if tok.op == "~" || tok.regex {
+		return nil, fmt.Errorf("%s can't be matched with a regex", field)
+	}
+
+	expr := numberExpr{field: field, op: tok.op}
+
+	low, high, isRange := strings.Cut(tok.value, "..")
+	if isRange && (tok.op == ":" || tok.op == "=") {
+		var err error
+		if low != "" {
+			expr.hasMin = true
+			if expr.min, err = parseNumber(field, low); err != nil {
+				return nil, err
+			}
+		}
+		if high != "" {
+			expr.hasMax = true
+			if expr.max, err = parseNumber(field, high); err != nil {
+				return nil, err
+			}
+		}
+
+		return expr, nil
+	}
+
+	number, err := parseNumber(field, tok.value)
+	if err != nil {
+		return nil, err
+	}
+
+	expr.min, expr.max = number, number
+	expr.hasMin, expr.hasMax = true, true
+
+	return expr, nil
+}
+
+// Helper: Parse a number, durations may be written as m:ss and booleans as true/false
+func parseNumber(field string, value string) (int, error) {
+	switch field {
+	case "starred":
+		switch strings.ToLower(value) {
+		case "true", "yes", "1":
+			return 1, nil
+		case "false", "no", "0":
+			return 0, nil
+		}
+		return 0, fmt.Errorf("starred must be true or false")
+
+	case "duration":
+		if minutes, seconds, found := strings.Cut(value, ":"); found {
+			m, errMinutes := strconv.Atoi(minutes)
+			s, errSeconds := strconv.Atoi(seconds)
+			if errMinutes != nil || errSeconds != nil {
+				return 0, fmt.Errorf("invalid duration %q", value)
+			}
+			return m*60 + s, nil
+		}
+	}
+
+	number, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("%s needs a number, got %q", field, value)
+	}
+
+	return number, nil
+}
+
+func displayField(field string) string {
+	if field == "" {
+		return "text"
+	}
+
+	return field
+}
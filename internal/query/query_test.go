@@ -0,0 +1,131 @@
+package query
+
+import "testing"
+
+var testFields = Fields{
+	Text: map[string][]string{
+		"title":  {"So What"},
+		"artist": {"Miles Davis"},
+		"album":  {"Kind of Blue"},
+		"genre":  {"Jazz"},
+		"path":   {"Jazz/Miles Davis/01 So What.flac"},
+	},
+	Numbers: map[string]int{
+		"year":     1959,
+		"rating":   5,
+		"played":   2,
+		"duration": 562,
+		"starred":  1,
+	},
+}
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		query string
+		want  bool
+	}{
+		// Bare words and fields
+		{"miles", true},
+		{"coltrane", false},
+		{"genre:jazz", true},
+		{"genre=jaz", false},
+		{"genre!=rock", true},
+		{"artist:davis year:1959", true},
+
+		// Precedence, AND binds tighter than OR
+		{"coltrane | miles blue", true},
+		{"coltrane | miles coltrane", false},
+		{"miles coltrane | blue", true},
+		{"(coltrane | miles) blue", true},
+		{"(coltrane | miles) (rock | pop)", false},
+		{"miles AND blue OR coltrane", true},
+		{"miles & coltrane", false},
+
+		// Operators end bare words
+		{"coltrane|miles", true},
+		{"miles&coltrane", false},
+		{"(coltrane|miles)", true},
+		{"miles(coltrane|blue)", true},
+		{"genre:rock|genre:jazz", true},
+
+		// NOT and -
+		{"-coltrane", true},
+		{"-miles", false},
+		{"NOT miles", false},
+		{"!genre:rock", true},
+		{"-(coltrane | rock)", true},
+		{"NOT NOT miles", true},
+		{"miles -year:1959", false},
+
+		// Numbers and ranges
+		{"year:1950..1969", true},
+		{"year:1960..", false},
+		{"year:..1959", true},
+		{"rating>=4", true},
+		{"rating<5", false},
+		{"played!=2", false},
+		{"starred:true", true},
+
+		// Durations as m:ss
+		{"duration:9:22", true},
+		{"duration>9:00", true},
+		{"duration:8:00..9:00", false},
+		{"duration<=562", true},
+
+		// Regex
+		{"title:/^so w/", true},
+		{"artist~dav(is|e)", true},
+		{"title:/a|b/", true},
+		{"artist:/^davis/", false},
+
+		// Quoting
+		{`artist:"miles davis"`, true},
+		{`artist="Miles Davis"`, true},
+		{`"kind of"`, true},
+		{`"miles | coltrane"`, false},
+		{`"NOT"`, false},
+		{`title:"say \"what\""`, false},
+	}
+
+	for _, test := range tests {
+		expr, err := Parse(test.query)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", test.query, err)
+			continue
+		}
+
+		if got := expr.Match(testFields); got != test.want {
+			t.Errorf("Parse(%q).Match = %v, want %v", test.query, got, test.want)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []string{
+		"(miles",
+		"miles)",
+		"|",
+		"miles |",
+		`artist:"miles`,
+		"title:/[/",
+		"year:abc",
+		"year~1959",
+		"duration:9:xx",
+		"starred:maybe",
+		"title>=a",
+		"bogus:1",
+	}
+
+	for _, query := range tests {
+		if _, err := Parse(query); err == nil {
+			t.Errorf("Parse(%q): expected an error", query)
+		}
+	}
+}
+
+func TestParseEmpty(t *testing.T) {
+	expr, err := Parse("   ")
+	if err != nil || expr != nil {
+		t.Errorf("Parse of a blank query = %v, %v, want nil, nil", expr, err)
+	}
+}
@@ -0,0 +1,168 @@
+package ui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Number of results per section of the search overview
+const (
+	allArtistCount = 5
+	allAlbumCount  = 5
+	allSongCount   = 10
+)
+
+const (
+	allHeader = iota
+	allArtist
+	allAlbum
+	allSong
+	allMore
+)
+
+// A row of the search overview, index points into the section or is the filter mode of a header/more row
+type allRow struct {
+	kind  int
+	index int
+}
+
+// Helper: Rows of the search overview, every section ends with a row to show all its results
+func allRows(m model) []allRow {
+	var rows []allRow
+
+	if len(m.allResults.Artists) > 0 {
+		rows = append(rows, allRow{kind: allHeader, index: filterArtist})
+		for i := range m.allResults.Artists {
+			rows = append(rows, allRow{kind: allArtist, index: i})
+		}
+		rows = append(rows, allRow{kind: allMore, index: filterArtist})
+	}
+
+	if len(m.allResults.Albums) > 0 {
+		rows = append(rows, allRow{kind: allHeader, index: filterAlbums})
+		for i := range m.allResults.Albums {
+			rows = append(rows, allRow{kind: allAlbum, index: i})
+		}
+		rows = append(rows, allRow{kind: allMore, index: filterAlbums})
+	}
+
+	songs := 0
+	for i, song := range m.allResults.Songs {
+		if m.hideFiltered && song.Filtered {
+			continue
+		}
+
+		if songs == 0 {
+			rows = append(rows, allRow{kind: allHeader, index: filterSongs})
+		}
+		rows = append(rows, allRow{kind: allSong, index: i})
+		songs++
+	}
+	if songs > 0 {
+		rows = append(rows, allRow{kind: allMore, index: filterSongs})
+	}
+
+	return rows
+}
+
+func isAllHeader(m model, index int) bool {
+	if m.viewMode != viewList || m.displayMode != displayAll {
+		return false
+	}
+
+	rows := allRows(m)
+	return index >= 0 && index < len(rows) && rows[index].kind == allHeader
+}
+
+// Helper: Keep the cursor off the header at the top of the overview
+func skipAllHeader(m model) model {
+	if isAllHeader(m, m.cursorMain) {
+		m.cursorMain++
+	}
+
+	return m
+}
+
+// Helper: Open the artist or album, play the song or expand the section under the cursor
+func openAllRow(m model) (tea.Model, tea.Cmd) {
+	rows := allRows(m)
+	if m.cursorMain < 0 || m.cursorMain >= len(rows) {
+		return m, nil
+	}
+
+	row := rows[m.cursorMain]
+	switch row.kind {
+	case allArtist:
+		m.pushView(m.allResults.Artists[row.index].Name)
+		m.loading = true
+		m.displayMode = displayAlbums
+		m.albums = nil
+
+		return m.requestList(getArtistAlbums(m.allResults.Artists[row.index].ID))
+
+	case allAlbum:
+		m.pushView(m.allResults.Albums[row.index].Name)
+		m.loading = true
+		m.displayMode = displaySongs
+		m.songs = nil
+
+		return m.requestAlbum(m.allResults.Albums[row.index].ID)
+
+	case allSong:
+		cmd := m.setQueueFrom(m.allResults.Songs, row.index)
+		return m, cmd
+
+	case allMore:
+		return expandAllSection(m, row.index)
+	}
+
+	return m, nil
+}
+
+// Helper: Overview rows of the selection that point at an artist, album or song
+func selectedAllRows(m model) []allRow {
+	if m.viewMode != viewList || m.displayMode != displayAll {
+		return nil
+	}
+
+	rows := allRows(m)
+
+	var selected []allRow
+	for _, i := range selectedIndices(m) {
+		if i < len(rows) && rows[i].kind != allHeader && rows[i].kind != allMore {
+			selected = append(selected, rows[i])
+		}
+	}
+
+	return selected
+}
+
+// Helper: ID and rating of the artist, album or song of an overview row
+func allRowItem(m model, row allRow) (string, int) {
+	switch row.kind {
+	case allArtist:
+		return m.allResults.Artists[row.index].ID, m.allResults.Artists[row.index].Rating
+	case allAlbum:
+		return m.allResults.Albums[row.index].ID, m.allResults.Albums[row.index].Rating
+	case allSong:
+		return m.allResults.Songs[row.index].ID, m.allResults.Songs[row.index].Rating
+	}
+
+	return "", 0
+}
+
+func (m *model) rateAllRow(row allRow, rating int) {
+	switch row.kind {
+	case allArtist:
+		m.allResults.Artists[row.index].Rating = rating
+	case allAlbum:
+		m.allResults.Albums[row.index].Rating = rating
+	case allSong:
+		m.allResults.Songs[row.index].Rating = rating
+	}
+}
+
+// Helper: Search again in a single filter mode, going back returns to the overview
+func expandAllSection(m model, mode int) (tea.Model, tea.Cmd) {
+	m.viewTyped = false
+	return searchIn(m, m.lastSearchQuery, mode)
+}
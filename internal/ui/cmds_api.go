@@ -1,7 +1,14 @@
 package ui
 
 import (
+	"fmt"
+	"log"
+	"math/rand"
+	"time"
+
 	"github.com/MattiaPun/SubTUI/v2/internal/api"
+	"github.com/MattiaPun/SubTUI/v2/internal/art"
+	"github.com/MattiaPun/SubTUI/v2/internal/playlist"
 	tea "github.com/charmbracelet/bubbletea"
 )
 
@@ -16,6 +23,19 @@ func attemptLoginCmd() tea.Cmd {
 	}
 }
 
+// The state is copied right away, the UI goes on changing it while the copy is written
+func saveStateCmd() tea.Cmd {
+	snapshot := api.SnapshotState()
+
+	return func() tea.Msg {
+		if err := api.SaveState(snapshot); err != nil {
+			log.Printf("[State] Failed to save state: %v", err)
+		}
+
+		return nil
+	}
+}
+
 func searchCmd(query string, mode int, offset int) tea.Cmd {
 	return func() tea.Msg {
 
@@ -25,27 +45,78 @@ func searchCmd(query string, mode int, offset int) tea.Cmd {
 			if err != nil {
 				return errMsg{err}
 			}
-			return songsResultMsg{songs}
+			return songsResultMsg{songs: songs}
 
 		case filterAlbums:
 			albums, err := api.SubsonicSearchAlbum(query, offset)
 			if err != nil {
 				return errMsg{err}
 			}
-			return albumsResultMsg{albums}
+			return albumsResultMsg{albums: albums}
 
 		case filterArtist:
 			artists, err := api.SubsonicSearchArtist(query, offset)
 			if err != nil {
 				return errMsg{err}
 			}
-			return artistsResultMsg{artists}
+			return artistsResultMsg{artists: artists}
+
+		case filterAll:
+			result, err := api.SubsonicSearchAll(query, allArtistCount, allAlbumCount, allSongCount)
+			if err != nil {
+				return errMsg{err}
+			}
+			return allResultMsg{result: result}
 		}
 
 		return nil
 	}
 }
 
+// Helper: Tag the result of a list request, results of older requests get dropped
+func tagListCmd(cmd tea.Cmd, gen int) tea.Cmd {
+	return func() tea.Msg {
+		switch msg := cmd().(type) {
+		case songsResultMsg:
+			msg.gen = gen
+			return msg
+		case albumsResultMsg:
+			msg.gen = gen
+			return msg
+		case artistsResultMsg:
+			msg.gen = gen
+			return msg
+		case smartPlaylistMsg:
+			msg.gen = gen
+			return msg
+		case allResultMsg:
+			msg.gen = gen
+			return msg
+		default:
+			return msg
+		}
+	}
+}
+
+func loadCoverCmd(id string) tea.Cmd {
+	return func() tea.Msg {
+		img, err := art.Load(id)
+		if err != nil {
+			log.Printf("[Art] Failed to load cover %s: %v", id, err)
+		}
+
+		return coverMsg{id: id, img: img}
+	}
+}
+
+func searchDebounceCmd(query string, gen int) tea.Cmd {
+	delay := time.Duration(api.AppConfig.App.SearchDebounce) * time.Millisecond
+
+	return tea.Tick(delay, func(t time.Time) tea.Msg {
+		return searchDebounceMsg{query: query, gen: gen}
+	})
+}
+
 func getAlbumSongs(albumID string, shuffled bool) tea.Cmd {
 	return func() tea.Msg {
 		songs, err := api.SubsonicGetAlbum(albumID)
@@ -56,7 +127,7 @@ func getAlbumSongs(albumID string, shuffled bool) tea.Cmd {
 		if shuffled {
 			return shuffledSongsMsg{songs, false}
 		} else {
-			return songsResultMsg{songs}
+			return songsResultMsg{songs: songs}
 		}
 	}
 }
@@ -67,7 +138,7 @@ func getAlbumList(searchType string, offset int) tea.Cmd {
 		if err != nil {
 			return errMsg{err}
 		}
-		return albumsResultMsg{albums}
+		return albumsResultMsg{albums: albums}
 	}
 }
 
@@ -77,7 +148,93 @@ func getArtistAlbums(artistID string) tea.Cmd {
 		if err != nil {
 			return errMsg{err}
 		}
-		return albumsResultMsg{albums}
+		return albumsResultMsg{albums: albums}
+	}
+}
+
+func getContinuationSongsCmd(seed api.Song, count int, play bool) tea.Cmd {
+	return func() tea.Msg {
+		var songs []api.Song
+		var err error
+
+		if seed.ArtistID != "" {
+			songs, err = api.SubsonicGetSimilarSongs2(seed.ArtistID, count)
+		}
+
+		// Fall back to random songs around the same genre and year
+		if err != nil || len(songs) == 0 {
+			fromYear, toYear := 0, 0
+			if seed.Year > 0 {
+				fromYear = seed.Year - 5
+				toYear = seed.Year + 5
+			}
+
+			songs, err = api.SubsonicGetRandomSongs(count, seed.Genre, fromYear, toYear)
+			if err != nil {
+				log.Printf("[Queue] Failed to continue queue: %v", err)
+			}
+		}
+
+		return continueQueueMsg{songs: songs, play: play}
+	}
+}
+
+func startRadioCmd(seed radioSeed, size int) tea.Cmd {
+	return func() tea.Msg {
+		var songs []api.Song
+		similarIDs := []string{}
+
+		if seed.song != nil {
+			songs = append(songs, *seed.song)
+		}
+
+		if seed.similarID != "" {
+			similarIDs = append(similarIDs, seed.similarID)
+		}
+
+		// Seed a playlist radio with a sample of its songs
+		if seed.playlistID != "" {
+			playlistSongs, err := api.SubsonicGetPlaylistSongs(seed.playlistID)
+			if err != nil {
+				return errMsg{err}
+			}
+
+			rand.Shuffle(len(playlistSongs), func(i, j int) {
+				playlistSongs[i], playlistSongs[j] = playlistSongs[j], playlistSongs[i]
+			})
+
+			for i := 0; i < len(playlistSongs) && i < 5; i++ {
+				songs = append(songs, playlistSongs[i])
+				similarIDs = append(similarIDs, playlistSongs[i].ID)
+			}
+		}
+
+		for _, id := range similarIDs {
+			similar, err := api.SubsonicGetSimilarSongs(id, size)
+			if err != nil {
+				log.Printf("[Radio] Failed to get similar songs: %v", err)
+				continue
+			}
+			songs = append(songs, similar...)
+		}
+
+		if seed.artistID != "" {
+			similar, err := api.SubsonicGetSimilarSongs2(seed.artistID, size)
+			if err != nil {
+				log.Printf("[Radio] Failed to get similar artist songs: %v", err)
+			}
+			songs = append(songs, similar...)
+		}
+
+		if seed.artistName != "" {
+			top, err := api.SubsonicGetTopSongs(seed.artistName, size)
+			if err != nil {
+				log.Printf("[Radio] Failed to get top songs: %v", err)
+			}
+			songs = append(songs, top...)
+		}
+
+		return radioResultMsg{songs: songs}
 	}
 }
 
@@ -101,7 +258,7 @@ func getPlaylistSongs(id string, shuffled bool) tea.Cmd {
 		if shuffled {
 			return shuffledSongsMsg{songs, true}
 		} else {
-			return songsResultMsg{songs}
+			return songsResultMsg{songs: songs}
 		}
 	}
 }
@@ -149,6 +306,117 @@ func getPlayQueue() tea.Cmd {
 
 }
 
+func exportPlaylistCmd(path string, name string, songs []api.Song, playlistID string) tea.Cmd {
+	return func() tea.Msg {
+		if playlistID != "" {
+			playlistSongs, err := api.SubsonicGetPlaylistSongs(playlistID)
+			if err != nil {
+				return reportMsg{title: "Export Failed", lines: []string{err.Error()}}
+			}
+			songs = playlistSongs
+		}
+
+		if err := playlist.Export(path, name, songs); err != nil {
+			return reportMsg{title: "Export Failed", lines: []string{err.Error()}}
+		}
+
+		return reportMsg{title: "Export", lines: []string{fmt.Sprintf("Exported %d songs to %s", len(songs), path)}}
+	}
+}
+
+func importPlaylistCmd(path string) tea.Cmd {
+	return func() tea.Msg {
+		entries, err := playlist.Import(path)
+		if err != nil {
+			return reportMsg{title: "Import Failed", lines: []string{err.Error()}}
+		}
+
+		songs, unmatched := playlist.Resolve(entries)
+		return importResultMsg{path: path, songs: songs, unmatched: unmatched}
+	}
+}
+
+func planSyncCmd(dir string) tea.Cmd {
+	return func() tea.Msg {
+		plans, err := playlist.PlanSync(dir)
+		if err != nil {
+			return reportMsg{title: "Sync Failed", lines: []string{err.Error()}}
+		}
+
+		return syncPlanMsg{plans: plans}
+	}
+}
+
+func applySyncCmd(plans []playlist.SyncPlan) tea.Cmd {
+	return func() tea.Msg {
+		if err := playlist.ApplySync(plans); err != nil {
+			return reportMsg{title: "Sync Failed", lines: []string{err.Error()}}
+		}
+
+		return reportMsg{title: "Sync", lines: []string{"Playlists synced"}}
+	}
+}
+
+func loadHistoryCmd(stats bool) tea.Cmd {
+	return func() tea.Msg {
+		events, err := api.LoadHistory()
+		if err != nil {
+			return errMsg{err}
+		}
+
+		return historyResultMsg{events: events, stats: stats}
+	}
+}
+
+func loadRecentPlaysCmd() tea.Cmd {
+	return func() tea.Msg {
+		events, err := api.LoadHistory()
+		if err != nil {
+			log.Printf("[History] Failed to load recent plays: %v", err)
+			return nil
+		}
+
+		return recentPlaysMsg{ids: recentPlays(events)}
+	}
+}
+
+func getUpNextCmd(ids []string) tea.Cmd {
+	return func() tea.Msg {
+		var songs []api.Song
+
+		if len(ids) > upNextRestoreLimit {
+			ids = ids[:upNextRestoreLimit]
+		}
+
+		for _, id := range ids {
+			song, err := api.SubsonicGetSong(id)
+			if err != nil || song.ID == "" {
+				log.Printf("[Up Next] Failed to get song %s: %v", id, err)
+				continue
+			}
+
+			songs = append(songs, song)
+		}
+
+		return upNextResultMsg{songs: songs}
+	}
+}
+
+func selectedAlbumsCmd(songs []api.Song, albumIDs []string, action int, playlistID string) tea.Cmd {
+	return func() tea.Msg {
+		for _, id := range albumIDs {
+			albumSongs, err := api.SubsonicGetAlbum(id)
+			if err != nil {
+				return errMsg{err}
+			}
+
+			songs = append(songs, albumSongs...)
+		}
+
+		return selectedSongsMsg{songs: songs, action: action, playlistID: playlistID}
+	}
+}
+
 func savePlayQueueCmd(ids []string, currentID string) tea.Cmd {
 	return func() tea.Msg {
 
@@ -160,11 +428,13 @@ func savePlayQueueCmd(ids []string, currentID string) tea.Cmd {
 	}
 }
 
-func addSongToPlaylistCmd(songID string, playlistID string) tea.Cmd {
+func addSongsToPlaylistCmd(songIDs []string, playlistID string) tea.Cmd {
 	return func() tea.Msg {
 
-		if songID != "" && playlistID != "" {
-			api.SubsonicAddToPlaylist(songID, playlistID)
+		if len(songIDs) != 0 && playlistID != "" {
+			if err := api.SubsonicAddToPlaylist(songIDs, playlistID); err != nil {
+				return reportMsg{title: "Add To Playlist Failed", lines: []string{err.Error()}}
+			}
 		}
 
 		return nil
@@ -12,3 +12,9 @@ func syncPlayerCmd() tea.Cmd {
 		return statusMsg(player.GetPlayerStatus())
 	})
 }
+
+func syncLevelsCmd(tick int) tea.Cmd {
+	return tea.Tick(time.Millisecond*100, func(t time.Time) tea.Msg {
+		return audioLevelsMsg{levels: player.GetAudioLevels(), tick: tick}
+	})
+}
@@ -0,0 +1,205 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/MattiaPun/SubTUI/v2/internal/api"
+	"github.com/MattiaPun/SubTUI/v2/internal/art"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Places a cover is drawn, kitty keeps one image per slot
+const (
+	coverSlotFooter = iota + 1
+	coverSlotDetail
+	coverSlotPlaying
+)
+
+// Number of covers the model keeps, the ones that are not shown are dropped when it is reached
+const coverLimit = 32
+
+// Images drawn by the last frame. View works on a copy of the model, so it is kept behind a pointer
+type coverFrame struct {
+	lines   []string       // Lines of the last frame, without the image escapes
+	placed  map[int]string // Image drawn per slot
+	pending []coverPlacement
+}
+
+// Image waiting to be drawn over its box once the frame is done
+type coverPlacement struct {
+	slot  int
+	key   string
+	image string
+	x     int
+	y     int
+	rows  int
+}
+
+// Helper: Covers belong to albums, songs without an album use their own
+func coverID(song api.Song) string {
+	if song.AlbumID != "" {
+		return song.AlbumID
+	}
+
+	return song.ID
+}
+
+// Helper: Load a cover once, covers that failed to load stay empty
+func (m *model) requestCover(id string) tea.Cmd {
+	if m.artProtocol == art.Off || id == "" {
+		return nil
+	}
+
+	if _, ok := m.covers[id]; ok {
+		return nil
+	}
+
+	// Dropped covers are still in the cache of art
+	if len(m.covers) >= coverLimit {
+		shown := map[string]bool{playingCover(*m): true, m.detailCover: true, albumCover(*m): true}
+		for cached := range m.covers {
+			if !shown[cached] {
+				delete(m.covers, cached)
+			}
+		}
+	}
+
+	m.covers[id] = nil
+	return loadCoverCmd(id)
+}
+
+// Helper: Cover of the song that is playing, empty if nothing plays
+func playingCover(m model) string {
+	song, ok := playingSong(m)
+	if !ok {
+		return ""
+	}
+
+	return coverID(song)
+}
+
+// Helper: Cover of the album under the cursor, shown next to the album list
+func albumCover(m model) string {
+	if m.displayMode != displayAlbums || m.cursorMain < 0 || m.cursorMain >= len(m.albums) {
+		return ""
+	}
+
+	return m.albums[m.cursorMain].ID
+}
+
+// Helper: Load the cover of the album under the cursor
+func (m model) requestAlbumCover() (model, tea.Cmd) {
+	cmd := m.requestCover(albumCover(m))
+	return m, cmd
+}
+
+// Helper: List with a cover on its right, x and y are the screen cell of the top left corner of the list
+func listWithCover(m model, id string, list func(width int) string, width int, height int, x int, y int) string {
+	coverRows := min(height-4, 12)
+	coverCols := coverRows * 2
+	listWidth := width - coverCols - 1
+
+	content := lipgloss.PlaceHorizontal(listWidth, lipgloss.Left, list(listWidth))
+	cover := coverBox(m, id, coverSlotDetail, coverCols, coverRows, x+listWidth, y)
+
+	return lipgloss.JoinHorizontal(lipgloss.Top, content, cover)
+}
+
+// Helper: Box of cols x rows cells with a cover, x and y are the screen cell of its top left corner
+func coverBox(m model, id string, slot int, cols int, rows int, x int, y int) string {
+	blank := strings.Repeat(" ", cols)
+	lines := make([]string, rows)
+	for i := range lines {
+		lines[i] = blank
+	}
+
+	img := m.covers[id]
+	if img == nil {
+		lines[0] += art.Clear(m.artProtocol, slot)
+		return strings.Join(lines, "\n")
+	}
+
+	image := art.Render(id, img, m.artProtocol, slot, cols, rows)
+	if m.artProtocol == art.Blocks {
+		return image
+	}
+
+	// Images are drawn by drawCovers once the whole frame is known
+	m.coverFrame.pending = append(m.coverFrame.pending, coverPlacement{
+		slot:  slot,
+		key:   fmt.Sprintf("%s/%d/%d/%d/%d", id, cols, rows, x, y),
+		image: image,
+		x:     x,
+		y:     y,
+		rows:  rows,
+	})
+
+	return strings.Join(lines, "\n")
+}
+
+// Helper: Draw the images of the frame's cover boxes. The terminal only writes the lines that changed,
+// so an image is sent again when its box changed or, for sixel and iTerm, when text is written over it.
+// The escape goes at the end of the lowest line of the box that is written, after the text of the box.
+func drawCovers(m model, view string) string {
+	frame := m.coverFrame
+	lines := strings.Split(view, "\n")
+	placed := make(map[int]string)
+
+	// Lines of the box that differ from what the terminal shows
+	written := func(p coverPlacement) []int {
+		var rows []int
+		for y := p.y; y < min(p.y+p.rows, len(lines)); y++ {
+			if y >= len(frame.lines) || lines[y] != frame.lines[y] {
+				rows = append(rows, y)
+			}
+		}
+		return rows
+	}
+
+	for _, p := range frame.pending {
+		placed[p.slot] = p.key
+		if p.y < 0 || p.y >= len(lines) {
+			continue
+		}
+
+		var rows []int
+		if m.artProtocol != art.Kitty {
+			rows = written(p)
+		}
+
+		host := p.y
+		switch {
+		case len(rows) > 0:
+			host = rows[len(rows)-1]
+		case frame.placed[p.slot] == p.key:
+			continue
+		}
+
+		line := lines[host] + art.Place(p.image, p.x, p.y)
+		if host < len(frame.lines) && strings.HasPrefix(frame.lines[host], line) {
+			switch {
+			case len(rows) == 1:
+				// Only the escape would change, the image is still on the screen
+				line = frame.lines[host]
+			case frame.lines[host] == line:
+				// Other lines of the box are written, an extra reset makes the terminal draw the image after them
+				line += "\x1b[m"
+			}
+		}
+		lines[host] = line
+	}
+
+	frame.lines = lines
+	frame.placed = placed
+	frame.pending = nil
+
+	return strings.Join(lines, "\n")
+}
+
+// Helper: Forget the drawn images, the terminal draws the whole screen again after a resize
+func (f *coverFrame) reset() {
+	f.lines = nil
+	f.placed = nil
+}
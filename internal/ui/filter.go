@@ -0,0 +1,355 @@
+package ui
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+
+	"github.com/MattiaPun/SubTUI/v2/internal/api"
+	"github.com/MattiaPun/SubTUI/v2/internal/query"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// A single exclusion filter, the label tells the user why a song was filtered
+type exclusionRule struct {
+	label string
+	expr  query.Expr
+}
+
+// Helper: Filters of a profile, the [filters] section when the profile is unknown
+func profileFilters(name string) api.Filters {
+	for _, profile := range api.AppConfig.FilterProfiles {
+		if profile.Name == name {
+			return profile.Filters
+		}
+	}
+
+	return api.AppConfig.Filters
+}
+
+// Helper: Compile the exclusion filters from the config into queries
+func exclusionRules(filters api.Filters) []exclusionRule {
+	var rules []exclusionRule
+
+	add := func(label string, field string, op string, value string) {
+		rule, err := query.Term(field, op, value)
+		if err != nil {
+			log.Printf("[Filters] Invalid %s filter %q: %v", field, value, err)
+			return
+		}
+		rules = append(rules, exclusionRule{label: label, expr: rule})
+	}
+
+	for _, title := range filters.Titles {
+		add("titles: "+title, "title", ":", title)
+	}
+	for _, artist := range filters.Artists {
+		add("artists: "+artist, "artist", "=", artist)
+	}
+	for _, albumArtist := range filters.AlbumArtists {
+		add("album_artists: "+albumArtist, "albumartist", "=", albumArtist)
+	}
+	for _, genre := range filters.Genres {
+		add("genres: "+genre, "genre", "=", genre)
+	}
+	for _, note := range filters.Notes {
+		add("notes: "+note, "note", ":", note)
+	}
+	for _, path := range filters.Paths {
+		add("paths: "+path, "path", ":", path)
+	}
+
+	if filters.MinDuration > 0 {
+		add(fmt.Sprintf("min_duration: %d", filters.MinDuration), "duration", "<=", strconv.Itoa(filters.MinDuration))
+	}
+	if filters.MaxPlayCount > 0 {
+		add(fmt.Sprintf("max_play_count: %d", filters.MaxPlayCount), "played", "<=", strconv.Itoa(filters.MaxPlayCount))
+	}
+	if filters.ExcludeFavorites {
+		add("exclude_favorites", "starred", ":", "true")
+	}
+	if filters.MaxRating > 0 && filters.MaxRating <= 5 {
+		add(fmt.Sprintf("max_rating: %d", filters.MaxRating), "rating", ":", fmt.Sprintf("1..%d", filters.MaxRating))
+	}
+
+	for _, rule := range filters.Rules {
+		expr, err := query.Parse(rule)
+		if err != nil {
+			log.Printf("[Filters] Invalid rule %q: %v", rule, err)
+			continue
+		}
+		if expr != nil {
+			rules = append(rules, exclusionRule{label: "rules: " + rule, expr: expr})
+		}
+	}
+
+	return rules
+}
+
+// Helper: Label of the first filter excluding the song, empty if none does
+func excludedBy(m model, song api.Song) string {
+	if len(m.exclusion) == 0 {
+		return ""
+	}
+
+	fields := query.SongFields(song, m.starredMap[song.ID])
+	for _, rule := range m.exclusion {
+		if rule.expr.Match(fields) {
+			return rule.label
+		}
+	}
+
+	return ""
+}
+
+func isSongExcluded(m model, song api.Song) bool {
+	return excludedBy(m, song) != ""
+}
+
+// Helper: Switch the active filter profile and re-evaluate everything shown
+func (m *model) setFilterProfile(name string) {
+	m.filterProfile = name
+	m.exclusion = exclusionRules(profileFilters(name))
+
+	m.restoreLists()
+	applyExclusionFilters(*m, m.songs)
+	applyExclusionFilters(*m, m.queue)
+	applyExclusionFilters(*m, m.queueOriginal)
+	applyExclusionFilters(*m, m.upNext)
+	applyExclusionFilters(*m, m.allResults.Songs)
+	m.filterLists()
+
+	// The song enqueued after the playing one may be excluded now
+	m.syncNextSong()
+}
+
+// Helper: Filter the current list with the query typed in the search bar
+func applyLiveFilter(m model, input string) model {
+	expr, err := query.Parse(input)
+	if err != nil {
+		m.liveError = err.Error()
+		return m
+	}
+
+	m.liveFilter = input
+	m.liveQuery = expr
+	m.liveError = ""
+
+	m.cursorMain = 0
+	m.mainOffset = 0
+	m.clearSelection()
+	m.filterLists()
+
+	return m
+}
+
+// Helper: Rebuild the shown lists from the full ones, applying the live filter, finder, hidden songs and sorting
+func (m *model) filterLists() {
+	narrow := m.findNarrows()
+	if m.liveQuery == nil && !m.hideFiltered && !m.isSorted() && !narrow {
+		m.restoreLists()
+		return
+	}
+
+	if !m.listsFiltered {
+		m.fullSongs, m.fullAlbums, m.fullArtists = m.songs, m.albums, m.artists
+		m.listsFiltered = true
+	}
+
+	m.songs = []api.Song{}
+	for _, song := range m.fullSongs {
+		if m.hideFiltered && song.Filtered {
+			continue
+		}
+
+		if narrow && !isFuzzyMatch(m.findQuery, findText(songFindFields(song))) {
+			continue
+		}
+
+		if m.liveQuery == nil || m.liveQuery.Match(query.SongFields(song, m.starredMap[song.ID])) {
+			m.songs = append(m.songs, song)
+		}
+	}
+
+	m.albums = []api.Album{}
+	for _, album := range m.fullAlbums {
+		if narrow && !isFuzzyMatch(m.findQuery, findText(albumFindFields(album))) {
+			continue
+		}
+
+		if m.liveQuery == nil || m.liveQuery.Match(query.AlbumFields(album, m.starredMap[album.ID])) {
+			m.albums = append(m.albums, album)
+		}
+	}
+
+	m.artists = []api.Artist{}
+	for _, artist := range m.fullArtists {
+		if narrow && !isFuzzyMatch(m.findQuery, artist.Name) {
+			continue
+		}
+
+		if m.liveQuery == nil || m.liveQuery.Match(query.ArtistFields(artist, m.starredMap[artist.ID])) {
+			m.artists = append(m.artists, artist)
+		}
+	}
+
+	sortSongs(m.songs, m.sorts[displaySongs])
+	sortAlbums(m.albums, m.sorts[displayAlbums])
+	sortArtists(m.artists, m.sorts[displayArtist])
+
+	m.clampCursor()
+}
+
+// Helper: Copy a changed rating to the full lists
+func (m *model) syncRating(id string, rating int) {
+	for i := range m.fullSongs {
+		if m.fullSongs[i].ID == id {
+			m.fullSongs[i].Rating = rating
+		}
+	}
+
+	for i := range m.fullAlbums {
+		if m.fullAlbums[i].ID == id {
+			m.fullAlbums[i].Rating = rating
+		}
+	}
+
+	for i := range m.fullArtists {
+		if m.fullArtists[i].ID == id {
+			m.fullArtists[i].Rating = rating
+		}
+	}
+}
+
+// Helper: Bring back the full lists
+func (m *model) restoreLists() {
+	if !m.listsFiltered {
+		return
+	}
+
+	m.songs, m.albums, m.artists = m.fullSongs, m.fullAlbums, m.fullArtists
+	m.fullSongs, m.fullAlbums, m.fullArtists = nil, nil, nil
+	m.listsFiltered = false
+	m.clampCursor()
+}
+
+func (m *model) clearLiveFilter() {
+	m.liveFilter = ""
+	m.liveQuery = nil
+	m.liveError = ""
+	m.filterLists()
+}
+
+// Helper: Drop the live filter and finder and bring back the full lists before they get replaced
+func (m *model) resetLists() {
+	m.findQuery = ""
+	m.liveFilter = ""
+	m.liveQuery = nil
+	m.liveError = ""
+	m.restoreLists()
+}
+
+// Helper: Keep the cursor inside the main list after it shrunk
+func (m *model) clampCursor() {
+	if m.viewMode != viewList {
+		return
+	}
+
+	m.cursorMain = max(0, min(m.cursorMain, mainListLen(*m)-1))
+	m.mainOffset = min(m.mainOffset, m.cursorMain)
+}
+
+// Helper: Profile names as shown in the popup, the [filters] section comes first
+func filterProfileNames() []string {
+	names := []string{""}
+	for _, profile := range api.AppConfig.FilterProfiles {
+		names = append(names, profile.Name)
+	}
+
+	return names
+}
+
+func openFilterProfiles(m model) model {
+	m.showProfiles = true
+	m.cursorPopup = 0
+
+	for i, name := range filterProfileNames() {
+		if name == m.filterProfile {
+			m.cursorPopup = i
+		}
+	}
+
+	return m
+}
+
+func toggleHideFiltered(m model) model {
+	m.hideFiltered = !m.hideFiltered
+	m.filterLists()
+
+	return skipHiddenRow(m)
+}
+
+// Helper: Filtered songs in the queue are kept but not shown while they are hidden, the playing song always shows
+func isHiddenQueueRow(m model, index int) bool {
+	return m.viewMode == viewQueue && m.hideFiltered && index != m.queueIndex &&
+		index >= 0 && index < len(m.queue) && m.queue[index].Filtered
+}
+
+// Helper: Rows the cursor steps over
+func isSkippedRow(m model, index int) bool {
+	return isAllHeader(m, index) || isHiddenQueueRow(m, index)
+}
+
+// Helper: Move the cursor forward off a row it can't rest on, or back when nothing follows
+func skipHiddenRow(m model) model {
+	listLen := mainListLen(m)
+
+	for isSkippedRow(m, m.cursorMain) && m.cursorMain < listLen-1 {
+		m.cursorMain++
+	}
+	for isSkippedRow(m, m.cursorMain) && m.cursorMain > 0 {
+		m.cursorMain--
+	}
+
+	return m
+}
+
+// Helper: Explain which filter excluded the song under the cursor
+func whyFiltered(m model) (tea.Model, tea.Cmd) {
+	var song api.Song
+
+	switch {
+	case m.viewMode == viewQueue && m.cursorMain < len(m.queue):
+		song = m.queue[m.cursorMain]
+	case m.viewMode == viewList && m.displayMode == displaySongs && m.cursorMain < len(m.songs):
+		song = m.songs[m.cursorMain]
+	case m.viewMode == viewList && m.displayMode == displayAll && m.cursorMain < len(allRows(m)):
+		row := allRows(m)[m.cursorMain]
+		if row.kind != allSong {
+			return m, nil
+		}
+		song = m.allResults.Songs[row.index]
+	default:
+		return m, nil
+	}
+
+	profile := m.filterProfile
+	if profile == "" {
+		profile = "Default"
+	}
+
+	reason := excludedBy(m, song)
+	if reason == "" {
+		reason = "not filtered"
+	}
+
+	return m.handleReport(reportMsg{
+		title: "Why Filtered",
+		lines: []string{
+			fmt.Sprintf("%s - %s", song.Artist, song.Title),
+			"",
+			"Profile: " + profile,
+			"Filter:  " + reason,
+		},
+	})
+}
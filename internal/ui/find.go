@@ -0,0 +1,291 @@
+package ui
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/MattiaPun/SubTUI/v2/internal/api"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Helper: Rune positions in the text of the pattern's runes, found in order. Spaces in the pattern are ignored
+func fuzzyMatch(pattern string, text string) ([]int, bool) {
+	runes := []rune(text)
+	positions := []int{}
+	i := 0
+
+	for _, r := range pattern {
+		if unicode.IsSpace(r) {
+			continue
+		}
+
+		r = unicode.ToLower(r)
+		for i < len(runes) && unicode.ToLower(runes[i]) != r {
+			i++
+		}
+		if i == len(runes) {
+			return nil, false
+		}
+
+		positions = append(positions, i)
+		i++
+	}
+
+	return positions, true
+}
+
+// Helper: Check if all runes of the pattern appear in order in the text
+func isFuzzyMatch(pattern string, text string) bool {
+	_, ok := fuzzyMatch(pattern, text)
+	return ok
+}
+
+func songFindFields(song api.Song) []string {
+	return []string{song.Title, song.Artist, song.Album}
+}
+
+func albumFindFields(album api.Album) []string {
+	return []string{album.Name, album.Artist}
+}
+
+// Helper: The finder searches the fields joined by spaces
+func findText(fields []string) string {
+	return strings.Join(fields, " ")
+}
+
+// Helper: Number of items the finder searches through
+func findListLen(m model) int {
+	if m.findFocus == focusSidebar {
+		return sidebarLen(m)
+	}
+
+	return mainListLen(m)
+}
+
+// Helper: Fields of an item of the searched list the finder looks through
+func findFields(m model, i int) []string {
+	if m.findFocus == focusSidebar {
+		return []string{sidebarName(m, i)}
+	}
+
+	if m.viewMode == viewQueue {
+		if i < len(m.queue) {
+			return songFindFields(m.queue[i])
+		}
+		return nil
+	}
+
+	switch m.displayMode {
+	case displaySongs:
+		if i < len(m.songs) {
+			return songFindFields(m.songs[i])
+		}
+	case displayAlbums:
+		if i < len(m.albums) {
+			return albumFindFields(m.albums[i])
+		}
+	case displayArtist:
+		if i < len(m.artists) {
+			return []string{m.artists[i].Name}
+		}
+	}
+
+	return nil
+}
+
+// Helper: Rune positions matched by the finder in each field of an item, false if the item doesn't match
+func findMatches(m model, i int) ([][]int, bool) {
+	if m.findQuery == "" {
+		return nil, false
+	}
+
+	fields := findFields(m, i)
+	if fields == nil {
+		return nil, false
+	}
+
+	positions, ok := fuzzyMatch(m.findQuery, findText(fields))
+	if !ok {
+		return nil, false
+	}
+
+	// Spaces of the pattern are skipped, so no position falls on the space between two fields
+	matches := make([][]int, len(fields))
+	field, start := 0, 0
+	for _, p := range positions {
+		for field < len(fields)-1 && p > start+utf8.RuneCountInString(fields[field]) {
+			start += utf8.RuneCountInString(fields[field]) + 1
+			field++
+		}
+		matches[field] = append(matches[field], p-start)
+	}
+
+	return matches, true
+}
+
+// Helper: Check if an item of the searched list matches the finder
+func isFindMatch(m model, i int) bool {
+	_, ok := findMatches(m, i)
+	return ok
+}
+
+// Characters of a row matched by the finder, collected while the row is built column by column
+type findMarks struct {
+	fields [][]int
+	runes  map[int]bool
+}
+
+// Helper: Finder matches of a row, empty when the finder searches another list or the row doesn't match
+func rowFindMarks(m model, focus int, i int) findMarks {
+	if m.findFocus != focus {
+		return findMarks{}
+	}
+
+	fields, _ := findMatches(m, i)
+	return findMarks{fields: fields, runes: map[int]bool{}}
+}
+
+// Helper: Mark the matched characters of a field that is shown as the next column of the row
+func (f findMarks) column(row string, column string, field int) {
+	if field >= len(f.fields) {
+		return
+	}
+
+	start := utf8.RuneCountInString(row)
+	length := utf8.RuneCountInString(column)
+	for _, p := range f.fields[field] {
+		if p < length {
+			f.runes[start+p] = true
+		}
+	}
+}
+
+// Helper: Render a row with the marked characters underlined and in bold
+func (f findMarks) render(style lipgloss.Style, row string) string {
+	if len(f.runes) == 0 {
+		return style.Render(row)
+	}
+
+	matchStyle := style.Underline(true).Bold(true)
+	runes := []rune(row)
+
+	var b strings.Builder
+	for start := 0; start < len(runes); {
+		end := start
+		for end < len(runes) && f.runes[end] == f.runes[start] {
+			end++
+		}
+
+		if f.runes[start] {
+			b.WriteString(matchStyle.Render(string(runes[start:end])))
+		} else {
+			b.WriteString(style.Render(string(runes[start:end])))
+		}
+		start = end
+	}
+
+	return b.String()
+}
+
+// Helper: Check if the finder narrows down the main list instead of only highlighting
+func (m model) findNarrows() bool {
+	return m.findQuery != "" && api.AppConfig.App.FindNarrow && m.findFocus == focusMain && m.viewMode == viewList
+}
+
+func openFind(m model) model {
+	if m.focus != focusMain && m.focus != focusSidebar {
+		return m
+	}
+
+	m.clearFind()
+	m.findFocus = m.focus
+	m.findStart = m.cursorMain
+	if m.findFocus == focusSidebar {
+		m.findStart = m.cursorSide
+	}
+
+	return openPrompt(m, promptFind, "")
+}
+
+// Helper: Update the finder while typing and jump to the first match
+func applyFind(m model, input string) model {
+	narrowed := m.findNarrows()
+	m.findQuery = input
+
+	if m.findNarrows() || narrowed {
+		m.filterLists()
+		m.findStart = 0
+	}
+
+	return jumpToMatch(m, m.findStart, true)
+}
+
+// Helper: Move the cursor to the first match from index on, wrapping around
+func jumpToMatch(m model, index int, forward bool) model {
+	total := findListLen(m)
+	if total == 0 {
+		return m
+	}
+
+	for step := 0; step < total; step++ {
+		i := index + step
+		if !forward {
+			i = index - step
+		}
+		i = ((i % total) + total) % total
+
+		if isFindMatch(m, i) {
+			m.moveFindCursor(i)
+			return m
+		}
+	}
+
+	return m
+}
+
+func findNext(m model, forward bool) model {
+	cursor := m.cursorMain
+	if m.findFocus == focusSidebar {
+		cursor = m.cursorSide
+	}
+
+	if forward {
+		return jumpToMatch(m, cursor+1, true)
+	}
+
+	return jumpToMatch(m, cursor-1, false)
+}
+
+// Helper: Put the cursor on an item and scroll it into view
+func (m *model) moveFindCursor(i int) {
+	if m.findFocus == focusSidebar {
+		m.cursorSide = i
+
+		// Same estimate as used when moving down the sidebar
+		footerHeight := max(int(float64(m.height)*0.10), 5)
+		visibleRows := max(m.height-1-footerHeight-(3*2)-6, 1)
+
+		if i < m.sideOffset || i >= m.sideOffset+visibleRows {
+			m.sideOffset = max(i-2, 0)
+		}
+		return
+	}
+
+	m.cursorMain = i
+
+	// Height - Search(3) - Footer(6) - Margins(4) - TableHeader(2) = 17
+	visibleRows := max(m.height-17-upNextHeight(*m), 1)
+	if i < m.mainOffset || i >= m.mainOffset+visibleRows {
+		m.mainOffset = max(i-2, 0)
+	}
+}
+
+func (m *model) clearFind() {
+	narrowed := m.findNarrows()
+	m.findQuery = ""
+
+	if narrowed {
+		m.filterLists()
+	}
+}
@@ -0,0 +1,215 @@
+package ui
+
+import (
+	"log"
+	"sort"
+	"time"
+
+	"github.com/MattiaPun/SubTUI/v2/internal/api"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	historySongsLimit = 500
+	skipMargin        = 10 // Seconds before the end of a song that still count as finished
+	statsTopLimit     = 5
+	listenStepLimit   = 2 // Seconds between two player statuses that still count as listening
+)
+
+type statsPeriod struct {
+	name string
+	days int
+}
+
+var statsPeriods = []statsPeriod{
+	{"Last 7 days", 7},
+	{"Last 30 days", 30},
+	{"Last year", 365},
+	{"All time", 0},
+}
+
+type statEntry struct {
+	name  string
+	count int
+}
+
+type historyStats struct {
+	plays     int
+	skips     int
+	finished  int
+	listened  float64
+	artists   []statEntry
+	albums    []statEntry
+	genres    []statEntry
+	skipRates []statEntry
+}
+
+func historyEvent(eventType string, song api.Song, listened float64) api.HistoryEvent {
+	return api.HistoryEvent{
+		Time:     time.Now(),
+		Type:     eventType,
+		SongID:   song.ID,
+		Title:    song.Title,
+		Artist:   song.Artist,
+		Album:    song.Album,
+		AlbumID:  song.AlbumID,
+		Genre:    song.Genre,
+		Duration: song.Duration,
+		Listened: listened,
+	}
+}
+
+func recordHistoryCmd(event api.HistoryEvent) tea.Cmd {
+	return func() tea.Msg {
+		if err := api.AppendHistory(event); err != nil {
+			log.Printf("[History] Failed to record %s: %v", event.Type, err)
+		}
+
+		return nil
+	}
+}
+
+// Helper: Record how long the tracked song was listened to and stop tracking it
+func (m *model) finishListening() tea.Cmd {
+	if m.listening.ID == "" {
+		return nil
+	}
+
+	// Skipped when playback stopped before the end, the time listened doesn't count seeking
+	eventType := api.HistoryFinish
+	if m.listening.Duration > 0 && m.listenPos < float64(m.listening.Duration-skipMargin) {
+		eventType = api.HistorySkip
+	}
+
+	cmd := recordHistoryCmd(historyEvent(eventType, m.listening, m.listened))
+	m.listening = api.Song{}
+	m.listened = 0
+	m.listenPos = 0
+
+	return cmd
+}
+
+// Helper: Add the playback since the last status to the time listened, jumps from seeking are left out
+func (m *model) trackListening(position float64) {
+	if step := position - m.listenPos; step > 0 && step <= listenStepLimit {
+		m.listened += step
+	}
+
+	m.listenPos = position
+}
+
+// Helper: Unique songs from the history, most recent first
+func historySongs(events []api.HistoryEvent) []api.Song {
+	seen := make(map[string]bool)
+	songs := []api.Song{}
+
+	for i := len(events) - 1; i >= 0 && len(songs) < historySongsLimit; i-- {
+		event := events[i]
+		if event.Type != api.HistoryStart || seen[event.SongID] {
+			continue
+		}
+
+		seen[event.SongID] = true
+		songs = append(songs, api.Song{
+			ID:       event.SongID,
+			Title:    event.Title,
+			Artist:   event.Artist,
+			Album:    event.Album,
+			AlbumID:  event.AlbumID,
+			Genre:    event.Genre,
+			Duration: event.Duration,
+		})
+	}
+
+	return songs
+}
+
+func computeStats(events []api.HistoryEvent, period statsPeriod) historyStats {
+	var stats historyStats
+
+	since := time.Time{}
+	if period.days > 0 {
+		since = time.Now().AddDate(0, 0, -period.days)
+	}
+
+	artists := make(map[string]int)
+	albums := make(map[string]int)
+	genres := make(map[string]int)
+	artistPlays := make(map[string]int)
+	artistSkips := make(map[string]int)
+
+	for _, event := range events {
+		if event.Time.Before(since) {
+			continue
+		}
+
+		switch event.Type {
+		case api.HistoryStart:
+			stats.plays++
+			if event.Artist != "" {
+				artists[event.Artist]++
+			}
+			if event.Album != "" {
+				albums[event.Album]++
+			}
+			if event.Genre != "" {
+				genres[event.Genre]++
+			}
+
+		case api.HistoryFinish:
+			stats.finished++
+			stats.listened += event.Listened
+			artistPlays[event.Artist]++
+
+		case api.HistorySkip:
+			stats.skips++
+			stats.listened += event.Listened
+			artistPlays[event.Artist]++
+			artistSkips[event.Artist]++
+		}
+	}
+
+	stats.artists = topEntries(artists)
+	stats.albums = topEntries(albums)
+	stats.genres = topEntries(genres)
+
+	// Most skipped artists, as a percentage of their plays
+	skipRates := make(map[string]int)
+	for artist, skips := range artistSkips {
+		if artist != "" {
+			skipRates[artist] = skips * 100 / artistPlays[artist]
+		}
+	}
+	stats.skipRates = topEntries(skipRates)
+
+	return stats
+}
+
+func topEntries(counts map[string]int) []statEntry {
+	entries := make([]statEntry, 0, len(counts))
+	for name, count := range counts {
+		entries = append(entries, statEntry{name: name, count: count})
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].count != entries[j].count {
+			return entries[i].count > entries[j].count
+		}
+		return entries[i].name < entries[j].name
+	})
+
+	if len(entries) > statsTopLimit {
+		entries = entries[:statsTopLimit]
+	}
+
+	return entries
+}
+
+// Helper: Overall skip rate as a percentage
+func (s historyStats) skipRate() int {
+	if s.skips+s.finished == 0 {
+		return 0
+	}
+
+	return s.skips * 100 / (s.skips + s.finished)
+}
@@ -1,12 +1,16 @@
 package ui
 
 import (
+	"image"
+
 	"github.com/MattiaPun/SubTUI/v2/internal/api"
+	"github.com/MattiaPun/SubTUI/v2/internal/art"
+	"github.com/MattiaPun/SubTUI/v2/internal/player"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 )
 
-func InitialModel() model {
+func InitialModel(mini bool) model {
 	ti := textinput.New()
 	ti.Placeholder = "Search songs..."
 	ti.Focus()
@@ -29,6 +33,11 @@ func InitialModel() model {
 		filterMode:       filterSongs,
 		displayMode:      displaySongs,
 		starredMap:       make(map[string]bool),
+		exclusion:        exclusionRules(profileFilters(api.AppState.Filters.Profile)),
+		filterProfile:    api.AppState.Filters.Profile,
+		hideFiltered:     api.AppConfig.App.HideFiltered,
+		selection:        make(map[int]bool),
+		smartSongs:       make(map[string][]api.Song),
 		lastPlayedSongID: "",
 		loginInputs:      initialLoginInputs(),
 		lastKey:          "",
@@ -37,12 +46,18 @@ func InitialModel() model {
 		helpModel:        NewHelpModel(),
 		discordRPC:       api.AppConfig.App.DiscordRPC,
 		notify:           api.AppConfig.App.Notifications,
+		levelMeter:       api.AppConfig.App.LevelMeter,
+		audioLevels:      player.AudioLevels{Left: player.SilenceLevel, Right: player.SilenceLevel},
+		artProtocol:      art.Detect(api.AppConfig.App.AlbumArt),
+		covers:           make(map[string]image.Image),
+		coverFrame:       &coverFrame{},
+		mini:             mini,
 	}
 }
 
 func (m model) Init() tea.Cmd {
 	var cmds []tea.Cmd
-	cmds = append(cmds, textinput.Blink)
+	cmds = append(cmds, textinput.Blink, loadRecentPlaysCmd())
 
 	if m.viewMode == viewList {
 		cmds = append(cmds, attemptLoginCmd())
@@ -0,0 +1,142 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/MattiaPun/SubTUI/v2/internal/api"
+	"github.com/MattiaPun/SubTUI/v2/internal/art"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mattn/go-runewidth"
+)
+
+// Helper: The mini player is also used when the terminal is too small for the full layout
+func showMini(m model) bool {
+	return m.viewMode != viewLogin && (m.mini || m.width < 50 || m.height < 25)
+}
+
+func toggleMini(m model) model {
+	m.mini = !m.mini
+	m.lastKey = ""
+
+	return m
+}
+
+// Media keys keep working in the mini player, everything else is handled here
+func miniMenu(key string, m model) (tea.Model, tea.Cmd) {
+	switch {
+	case keyMatches(key, api.AppConfig.Keybinds.Other.ToggleMini):
+		return toggleMini(m), nil
+	case keyMatches(key, api.AppConfig.Keybinds.Global.Quit):
+		return quit(m, nil)
+	case keyMatches(key, api.AppConfig.Keybinds.Favorites.ToggleFavorite):
+		return starPlaying(m)
+	case keyMatches(key, api.AppConfig.Keybinds.NowPlaying.RateUp):
+		return ratePlaying(m, 1)
+	case keyMatches(key, api.AppConfig.Keybinds.NowPlaying.RateDown):
+		return ratePlaying(m, -1)
+	}
+
+	return m, nil
+}
+
+// Helper: Play state, shuffle, loop, volume and star of the playing song as short icons
+func miniIcons(m model) string {
+	icons := []string{"▶"}
+	if m.playerStatus.Paused {
+		icons[0] = "⏸"
+	}
+
+	if m.shuffled {
+		icons = append(icons, "⇄")
+	}
+
+	switch m.loopMode {
+	case LoopAll:
+		icons = append(icons, "↻")
+	case LoopOne:
+		icons = append(icons, "↻1")
+	}
+
+	if m.playerStatus.Muted {
+		icons = append(icons, "♪ muted")
+	} else if m.playerStatus.Volume != 100 {
+		icons = append(icons, fmt.Sprintf("♪ %v%%", m.playerStatus.Volume))
+	}
+
+	if song, ok := playingSong(m); ok && m.starredMap[song.ID] {
+		icons = append(icons, "★")
+	}
+
+	return strings.Join(icons, " ")
+}
+
+// Helper: Elapsed time, bar and duration, the bar takes the width that is left
+func miniProgress(m model, width int) string {
+	current, duration := m.playerStatus.Current, m.playerStatus.Duration
+
+	left := formatDuration(int(current))
+	right := formatDuration(int(duration))
+
+	barWidth := width - len(left) - len(right) - 2
+	if barWidth < 4 {
+		return left + "/" + right
+	}
+
+	percent := 0.0
+	if duration > 0 {
+		percent = min(current/duration, 1)
+	}
+	filled := int(percent * float64(barWidth))
+
+	bar := lipgloss.NewStyle().Foreground(Theme.Special).Render(strings.Repeat("━", filled)) +
+		lipgloss.NewStyle().Foreground(Theme.Subtle).Render(strings.Repeat("─", barWidth-filled))
+
+	return fmt.Sprintf("%s %s %s", left, bar, right)
+}
+
+// Helper: Player in one or two lines, for tmux splits and terminals too small for the full layout
+func miniView(m model) string {
+	width := m.width
+	subtle := lipgloss.NewStyle().Foreground(Theme.Subtle)
+	highlight := lipgloss.NewStyle().Bold(true).Foreground(Theme.Highlight)
+
+	title, artist := "Nothing playing", ""
+	if song, ok := playingSong(m); ok {
+		title, artist = song.Title, song.Artist
+	}
+
+	icons := miniIcons(m)
+	iconsWidth := runewidth.StringWidth(icons)
+
+	// Images of the other views are not part of this one
+	clear := art.Clear(m.artProtocol, coverSlotFooter) + art.Clear(m.artProtocol, coverSlotDetail) + art.Clear(m.artProtocol, coverSlotPlaying)
+
+	if m.height < 2 {
+		// Song, progress and icons share the line, the song gets what the rest leaves
+		progress := miniProgress(m, min(width/3, 40))
+		songWidth := width - lipgloss.Width(progress) - iconsWidth - 4
+
+		text := title
+		if artist != "" {
+			text += " - " + artist
+		}
+
+		return clear + fmt.Sprintf("%s %s  %s", icons, highlight.Render(LimitString(text, songWidth)), progress)
+	}
+
+	nameWidth := max(width-iconsWidth-1, 0)
+	name := highlight.Render(title)
+	if artist != "" {
+		name += subtle.Render(" - " + artist)
+	}
+	if runewidth.StringWidth(title+" - "+artist) > nameWidth {
+		name = highlight.Render(LimitString(title+" - "+artist, nameWidth))
+	}
+
+	top := lipgloss.NewStyle().Width(nameWidth).Render(name) + " " + icons
+	bottom := miniProgress(m, width)
+
+	return clear + lipgloss.PlaceVertical(m.height, lipgloss.Top, top+"\n"+bottom)
+}
@@ -1,11 +1,15 @@
 package ui
 
 import (
+	"image"
 	"time"
 
 	"github.com/MattiaPun/SubTUI/v2/internal/api"
+	"github.com/MattiaPun/SubTUI/v2/internal/art"
 	"github.com/MattiaPun/SubTUI/v2/internal/integration"
 	"github.com/MattiaPun/SubTUI/v2/internal/player"
+	"github.com/MattiaPun/SubTUI/v2/internal/playlist"
+	"github.com/MattiaPun/SubTUI/v2/internal/query"
 	"github.com/charmbracelet/bubbles/textinput"
 )
 
@@ -17,7 +21,9 @@ type model struct {
 	songs        []api.Song
 	albums       []api.Album
 	artists      []api.Artist
+	allResults   api.SearchResult3
 	playlists    []api.Playlist
+	smartSongs   map[string][]api.Song
 	playerStatus player.PlayerStatus
 
 	// Navigation State
@@ -46,43 +52,123 @@ type model struct {
 	loginErr         string
 	discordRPC       bool
 	notify           bool
+	levelMeter       bool
+	levelMeterTick   int
+	audioLevels      player.AudioLevels
 
 	// Integrations
 	dbusInstance    *integration.Instance
 	discordInstance *integration.DiscordInstance
 
 	// Queue System
-	queue      []api.Song
-	queueIndex int
-	loopMode   int
+	queue         []api.Song
+	queueIndex    int
+	loopMode      int
+	queueHistory  []queueSnapshot
+	queueFuture   []queueSnapshot
+	shuffled      bool
+	queueOriginal []api.Song
+	continuing    bool
+	upNext        []api.Song
+	recentPlays   []string
+	queueEntries  int
 
 	// Stars
 	starredMap map[string]bool
 
+	// Album Art
+	artProtocol art.Protocol
+	covers      map[string]image.Image
+	detailCover string
+	coverFrame  *coverFrame
+
+	// Filters
+	exclusion     []exclusionRule
+	filterProfile string
+	hideFiltered  bool
+	liveFilter    string
+	liveQuery     query.Expr
+	liveError     string
+	listsFiltered bool
+	fullSongs     []api.Song
+	fullAlbums    []api.Album
+	fullArtists   []api.Artist
+	sorts         [3]sortState
+	findQuery     string
+	findFocus     int
+	findStart     int
+
+	// Play History
+	listening   api.Song
+	listened    float64
+	listenPos   float64
+	history     []api.HistoryEvent
+	stats       historyStats
+	statsPeriod int
+
+	// Selection State
+	selection    map[int]bool
+	visualMode   bool
+	visualAnchor int
+
 	// Login State
 	loginInputs []textinput.Model
 	loginFocus  int
 
 	// Input State
-	lastKey string
+	lastKey      string
+	prompt       int
+	promptTarget string
+	promptName   string
 
 	// Overlay States
 	showHelp      bool
 	showPlaylists bool
 	showRating    bool
+	showStats     bool
+	showReport    bool
+	showProfiles  bool
+	showPlaying   bool
+	mini          bool
+	reportTitle   string
+	reportLines   []string
+	syncPlans     []playlist.SyncPlan
 	helpModel     HelpModel
 
 	// Pagination State
 	lastSearchQuery string
+	lastSearchMode  int
 	albumListType   string
 	pageOffset      int
 	pageHasMore     bool
 
+	// Navigation History
+	navBack    []navView
+	navForward []navView
+	viewTitle  string
+	viewTyped  bool
+
+	// Search History
+	historyPos   int
+	historyDraft string
+
+	// Request State
+	listGen   int
+	typingGen int
+
 	// Mouse state
 	lastClickTime time.Time
 	lastClickId   string
 }
 
+type queueSnapshot struct {
+	queue         []api.Song
+	queueIndex    int
+	shuffled      bool
+	queueOriginal []api.Song
+	upNext        []api.Song
+}
+
 type HelpModel struct {
 	Width  int
 	Height int
@@ -102,14 +188,27 @@ type loginResultMsg struct {
 
 type songsResultMsg struct {
 	songs []api.Song
+	gen   int
 }
 
 type albumsResultMsg struct {
 	albums []api.Album
+	gen    int
 }
 
 type artistsResultMsg struct {
 	artists []api.Artist
+	gen     int
+}
+
+type allResultMsg struct {
+	result api.SearchResult3
+	gen    int
+}
+
+type searchDebounceMsg struct {
+	query string
+	gen   int
 }
 
 type playlistResultMsg struct {
@@ -121,10 +220,75 @@ type shuffledSongsMsg struct {
 	updateView bool
 }
 
+type radioSeed struct {
+	song       *api.Song
+	similarID  string
+	artistID   string
+	artistName string
+	playlistID string
+}
+
+type radioResultMsg struct {
+	songs []api.Song
+}
+
+type continueQueueMsg struct {
+	songs []api.Song
+	play  bool
+}
+
 type starredResultMsg struct {
 	result *api.SearchResult3
 }
 
+type reportMsg struct {
+	title string
+	lines []string
+}
+
+type smartPlaylistMsg struct {
+	name     string
+	songs    []api.Song
+	shuffled bool
+	gen      int
+}
+
+type syncPlanMsg struct {
+	plans []playlist.SyncPlan
+}
+
+type importResultMsg struct {
+	path      string
+	songs     []api.Song
+	unmatched []playlist.Entry
+}
+
+type historyResultMsg struct {
+	events []api.HistoryEvent
+	stats  bool
+}
+
+type recentPlaysMsg struct {
+	ids []string
+}
+
+// What is done with the selected songs once they are known
+const (
+	selectionQueueNext = iota
+	selectionQueueLast
+	selectionPlaylist
+)
+
+type selectedSongsMsg struct {
+	songs      []api.Song
+	action     int
+	playlistID string
+}
+
+type upNextResultMsg struct {
+	songs []api.Song
+}
+
 type playQueueResultMsg struct {
 	result *api.PlayQueue
 }
@@ -135,12 +299,22 @@ type createShareMsg struct {
 	url string
 }
 
+type coverMsg struct {
+	id  string
+	img image.Image
+}
+
 type errMsg struct {
 	err error
 }
 
 type statusMsg player.PlayerStatus
 
+type audioLevelsMsg struct {
+	levels player.AudioLevels
+	tick   int
+}
+
 type SetDBusMsg struct {
 	Instance *integration.Instance
 }
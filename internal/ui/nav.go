@@ -0,0 +1,155 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/MattiaPun/SubTUI/v2/internal/api"
+	"github.com/mattn/go-runewidth"
+)
+
+const navHistoryLimit = 50
+
+// A list view as it was left, restored by going back or forward
+type navView struct {
+	title         string
+	displayMode   int
+	songs         []api.Song
+	albums        []api.Album
+	artists       []api.Artist
+	allResults    api.SearchResult3
+	cursorMain    int
+	mainOffset    int
+	searchQuery   string
+	searchMode    int
+	albumListType string
+	pageOffset    int
+	pageHasMore   bool
+	cover         string
+}
+
+// Helper: Snapshot of the shown list, without the live filter or finder narrowing it
+func (m model) currentView() navView {
+	view := navView{
+		title:         m.viewTitle,
+		displayMode:   m.displayMode,
+		songs:         m.songs,
+		albums:        m.albums,
+		artists:       m.artists,
+		allResults:    m.allResults,
+		cursorMain:    m.cursorMain,
+		mainOffset:    m.mainOffset,
+		searchQuery:   m.lastSearchQuery,
+		searchMode:    m.lastSearchMode,
+		albumListType: m.albumListType,
+		pageOffset:    m.pageOffset,
+		pageHasMore:   m.pageHasMore,
+		cover:         m.detailCover,
+	}
+
+	if m.listsFiltered {
+		view.songs, view.albums, view.artists = m.fullSongs, m.fullAlbums, m.fullArtists
+	}
+
+	if m.viewMode == viewQueue {
+		view.displayMode = m.displayModePrev
+		view.cursorMain, view.mainOffset = 0, 0
+	}
+
+	return view
+}
+
+// Helper: Remember the shown list before another one replaces it
+func (m *model) pushView(title string) {
+	// A view that is still loading or opened again is replaced instead
+	if !m.loading && m.viewTitle != "" && m.viewTitle != title {
+		m.navBack = append(m.navBack, m.currentView())
+		if len(m.navBack) > navHistoryLimit {
+			m.navBack = m.navBack[len(m.navBack)-navHistoryLimit:]
+		}
+	}
+
+	m.navForward = nil
+	m.viewTitle = title
+	m.viewTyped = false
+	m.detailCover = ""
+}
+
+func (m *model) restoreView(view navView) {
+	m.resetLists()
+	m.clearSelection()
+
+	// Results of the view that is left are not needed anymore
+	m.listGen++
+	m.loading = false
+
+	m.viewMode = viewList
+	m.viewTitle = view.title
+	m.viewTyped = false
+	m.displayMode = view.displayMode
+	m.songs = applyExclusionFilters(*m, view.songs)
+	m.albums = view.albums
+	m.artists = view.artists
+	m.allResults = view.allResults
+	applyExclusionFilters(*m, m.allResults.Songs)
+	m.cursorMain = view.cursorMain
+	m.mainOffset = view.mainOffset
+	m.lastSearchQuery = view.searchQuery
+	m.lastSearchMode = view.searchMode
+	m.albumListType = view.albumListType
+	m.pageOffset = view.pageOffset
+	m.pageHasMore = view.pageHasMore
+	m.detailCover = view.cover
+
+	m.filterLists()
+	*m = skipAllHeader(*m)
+}
+
+// Helper: Go to the previous list view, the shown one can be reached again with forward
+func navigateBack(m model) model {
+	if len(m.navBack) == 0 {
+		return m
+	}
+
+	view := m.navBack[len(m.navBack)-1]
+	m.navBack = m.navBack[:len(m.navBack)-1]
+	if !m.loading {
+		m.navForward = append(m.navForward, m.currentView())
+	}
+	m.restoreView(view)
+
+	return m
+}
+
+func navigateForward(m model) model {
+	if len(m.navForward) == 0 {
+		return m
+	}
+
+	view := m.navForward[len(m.navForward)-1]
+	m.navForward = m.navForward[:len(m.navForward)-1]
+	if !m.loading {
+		m.navBack = append(m.navBack, m.currentView())
+	}
+	m.restoreView(view)
+
+	return m
+}
+
+// Helper: Titles of the previous views and the shown one, oldest parts are dropped when it gets too long
+func breadcrumb(m model, width int) string {
+	var titles []string
+	for _, view := range m.navBack {
+		titles = append(titles, view.title)
+	}
+	if m.viewTitle != "" {
+		titles = append(titles, m.viewTitle)
+	}
+
+	crumbs := strings.Join(titles, " › ")
+	for len(titles) > 1 && runewidth.StringWidth(crumbs) > width {
+		titles = titles[1:]
+		crumbs = "… › " + strings.Join(titles, " › ")
+	}
+
+	return strings.TrimRight(LimitString(crumbs, width), " ")
+}
@@ -0,0 +1,370 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/MattiaPun/SubTUI/v2/internal/api"
+	"github.com/MattiaPun/SubTUI/v2/internal/art"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Number of upcoming songs listed in the now playing view
+const nowPlayingUpcoming = 5
+
+// Helper: Song that is playing, false if nothing plays
+func playingSong(m model) (api.Song, bool) {
+	if m.lastPlayedSongID == "" || m.queueIndex < 0 || m.queueIndex >= len(m.queue) {
+		return api.Song{}, false
+	}
+
+	return m.queue[m.queueIndex], true
+}
+
+// Helper: Songs that play after the current one, up next first
+func upcomingSongs(m model, limit int) []api.Song {
+	songs := append([]api.Song{}, m.upNext...)
+
+	if m.loopMode != LoopOne {
+		for i := m.queueIndex + 1; i < len(m.queue) && len(songs) < limit; i++ {
+			songs = append(songs, m.queue[i])
+		}
+
+		if m.loopMode == LoopAll {
+			for i := 0; i < m.queueIndex && len(songs) < limit; i++ {
+				songs = append(songs, m.queue[i])
+			}
+		}
+	}
+
+	if len(songs) > limit {
+		songs = songs[:limit]
+	}
+
+	return songs
+}
+
+func toggleNowPlaying(m model) model {
+	m.showPlaying = !m.showPlaying
+	m.lastKey = ""
+
+	return m
+}
+
+// Media keys keep working in the now playing view, everything else is handled here
+func isMediaKey(key string) bool {
+	media := api.AppConfig.Keybinds.Media
+
+	for _, bindings := range [][]string{
+		media.PlayPause, media.Next, media.Prev, media.Shuffle, media.Loop, media.Restart,
+		media.Rewind, media.Forward, media.VolumeUp, media.VolumeDown, media.Mute,
+		api.AppConfig.Keybinds.Other.ToggleLevelMeter, api.AppConfig.Keybinds.Other.ToggleNotifications,
+	} {
+		if keyMatches(key, bindings) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func nowPlayingMenu(key string, m model) (tea.Model, tea.Cmd) {
+	switch {
+	case keyMatches(key, api.AppConfig.Keybinds.Global.Back) || keyMatches(key, api.AppConfig.Keybinds.NowPlaying.Toggle):
+		return toggleNowPlaying(m), nil
+	case keyMatches(key, api.AppConfig.Keybinds.Global.Quit):
+		return quit(m, nil)
+	case keyMatches(key, api.AppConfig.Keybinds.NowPlaying.RateUp):
+		return ratePlaying(m, 1)
+	case keyMatches(key, api.AppConfig.Keybinds.NowPlaying.RateDown):
+		return ratePlaying(m, -1)
+	case keyMatches(key, api.AppConfig.Keybinds.Favorites.ToggleFavorite):
+		return starPlaying(m)
+	case keyMatches(key, api.AppConfig.Keybinds.NowPlaying.GoToAlbum):
+		return openPlayingAlbum(m)
+	}
+
+	return m, nil
+}
+
+func ratePlaying(m model, step int) (model, tea.Cmd) {
+	song, ok := playingSong(m)
+	if !ok || song.ID == "" {
+		return m, nil
+	}
+
+	rating := min(max(song.Rating+step, 0), 5)
+	if rating == song.Rating {
+		return m, nil
+	}
+
+	for i := range m.queue {
+		if m.queue[i].ID == song.ID {
+			m.queue[i].Rating = rating
+		}
+	}
+
+	for i := range m.songs {
+		if m.songs[i].ID == song.ID {
+			m.songs[i].Rating = rating
+		}
+	}
+	m.syncRating(song.ID, rating)
+
+	return m, addRatingCmd(song.ID, rating)
+}
+
+func starPlaying(m model) (model, tea.Cmd) {
+	song, ok := playingSong(m)
+	if !ok || song.ID == "" {
+		return m, nil
+	}
+
+	starred := m.starredMap[song.ID]
+	if starred {
+		delete(m.starredMap, song.ID)
+	} else {
+		m.starredMap[song.ID] = true
+	}
+
+	return m, toggleStarCmd(song.ID, starred)
+}
+
+func openPlayingAlbum(m model) (model, tea.Cmd) {
+	song, ok := playingSong(m)
+	if !ok || song.AlbumID == "" {
+		return m, nil
+	}
+
+	m.showPlaying = false
+	m.clearSelection()
+
+	m.pushView(song.Album)
+	m.loading = true
+	m.mainOffset = 0
+	m.cursorMain = 0
+	m.lastSearchQuery = ""
+
+	m.focus = focusMain
+	m.viewMode = viewList
+	m.displayMode = displaySongs
+
+	return m.requestAlbum(song.AlbumID)
+}
+
+// Helper: Stars out of five, like the rating column
+func ratingStars(rating int) string {
+	return strings.Repeat("★", rating) + strings.Repeat("☆", 5-rating)
+}
+
+// Helper: Format, bitrate and sample rate of a song, skipping what the server doesn't report
+func technicalInfo(song api.Song) string {
+	var parts []string
+
+	if song.Suffix != "" {
+		parts = append(parts, strings.ToUpper(song.Suffix))
+	}
+	if song.BitRate > 0 {
+		parts = append(parts, fmt.Sprintf("%d kbps", song.BitRate))
+	}
+	if song.SamplingRate > 0 {
+		parts = append(parts, fmt.Sprintf("%g kHz", float64(song.SamplingRate)/1000))
+	}
+	if song.BitDepth > 0 {
+		parts = append(parts, fmt.Sprintf("%d bit", song.BitDepth))
+	}
+
+	return strings.Join(parts, " · ")
+}
+
+func replayGainInfo(m model, song api.Song) string {
+	mode := m.replayGainMode()
+	if mode == "no" {
+		return "off"
+	}
+
+	gain := song.ReplayGain.TrackGain
+	if mode == "album" {
+		gain = song.ReplayGain.AlbumGain
+	}
+
+	if gain == 0 {
+		return mode + ", no gain reported"
+	}
+
+	return fmt.Sprintf("%s, %+.2f dB", mode, gain)
+}
+
+// Helper: Progress bar with elapsed and remaining time and the percentage played
+func nowPlayingProgress(m model, width int) string {
+	current, duration := m.playerStatus.Current, m.playerStatus.Duration
+
+	percent := 0.0
+	if duration > 0 {
+		percent = min(current/duration, 1)
+	}
+
+	left := formatDuration(int(current))
+	right := fmt.Sprintf("-%s / %s  %3d%%", formatDuration(int(max(duration-current, 0))), formatDuration(int(duration)), int(percent*100))
+
+	barWidth := max(width-lipgloss.Width(left)-lipgloss.Width(right)-4, 10)
+	filled := int(percent * float64(barWidth))
+
+	bar := lipgloss.NewStyle().Foreground(Theme.Special).Render(strings.Repeat("━", filled)) +
+		lipgloss.NewStyle().Foreground(Theme.Subtle).Render(strings.Repeat("─", barWidth-filled))
+
+	return fmt.Sprintf("%s  %s  %s", left, bar, right)
+}
+
+// Helper: Cover of the playing song, or a framed note when there is none to draw
+func nowPlayingCover(m model, id string, cols int, rows int, x int, y int) string {
+	if m.artProtocol != art.Off && m.covers[id] != nil {
+		return coverBox(m, id, coverSlotPlaying, cols, rows, x, y)
+	}
+
+	placeholder := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(Theme.Subtle).
+		Foreground(Theme.Subtle).
+		Width(cols-2).
+		Height(rows-2).
+		Align(lipgloss.Center, lipgloss.Center).
+		Render("♪")
+
+	return art.Clear(m.artProtocol, coverSlotPlaying) + placeholder
+}
+
+func nowPlayingView(m model) string {
+	width := m.width - 2
+	height := m.height - 2
+
+	bold := lipgloss.NewStyle().Bold(true)
+	subtle := lipgloss.NewStyle().Foreground(Theme.Subtle)
+	label := func(name string) string {
+		return subtle.Render(fmt.Sprintf("%-12s", name))
+	}
+
+	song, ok := playingSong(m)
+
+	// Cover on the left, next to the song info when there is room for both
+	coverRows := min(height/2, 16)
+	coverCols := coverRows * 2
+	showCover := width >= coverCols+40
+	infoWidth := width - 4
+	if showCover {
+		infoWidth -= coverCols + 3
+	}
+
+	var info []string
+	add := func(name string, value string) {
+		info = append(info, label(name)+LimitString(value, infoWidth-12))
+	}
+
+	if !ok {
+		info = append(info, bold.Foreground(Theme.Highlight).Render("Nothing playing"))
+	} else {
+		// Long names wrap instead of being cut off
+		info = append(info,
+			bold.Foreground(Theme.Highlight).Width(infoWidth).Render(strings.ToUpper(song.Title)),
+			"",
+			bold.Width(infoWidth).Render(song.Artist),
+			lipgloss.NewStyle().Width(infoWidth).Render(song.Album),
+			"",
+		)
+
+		if song.Year > 0 {
+			add("Year", fmt.Sprint(song.Year))
+		}
+		if song.TrackNumber > 0 {
+			add("Track", fmt.Sprintf("%d, disc %d", song.TrackNumber, max(song.DiscNumber, 1)))
+		}
+		if song.Genre != "" {
+			add("Genre", song.Genre)
+		}
+		if details := technicalInfo(song); details != "" {
+			add("Format", details)
+		}
+		add("ReplayGain", replayGainInfo(m, song))
+		add("Rating", ratingStars(song.Rating))
+
+		star := "☆ Not starred"
+		if m.starredMap[song.ID] {
+			star = "★ Starred"
+		}
+		add("Favorite", star)
+		add("Plays", fmt.Sprint(song.PlayCount))
+	}
+
+	top := strings.Join(info, "\n")
+
+	if showCover {
+		cover := nowPlayingCover(m, coverID(song), coverCols, coverRows, 3, 2)
+		top = lipgloss.JoinHorizontal(lipgloss.Top, cover, "   ", top)
+	}
+
+	// Playback state under the progress bar
+	var state []string
+	if m.playerStatus.Paused {
+		state = append(state, "[Paused]")
+	}
+	if m.shuffled {
+		state = append(state, "[Shuffle]")
+	}
+	switch m.loopMode {
+	case LoopAll:
+		state = append(state, "[Loop all]")
+	case LoopOne:
+		state = append(state, "[Loop one]")
+	}
+	if m.playerStatus.Muted {
+		state = append(state, "[Muted]")
+	} else {
+		state = append(state, fmt.Sprintf("[%v%%]", m.playerStatus.Volume))
+	}
+	if !m.notify {
+		state = append(state, "[Silent]")
+	}
+
+	progress := nowPlayingProgress(m, width-4) + "\n" + subtle.Render(strings.Join(state, " "))
+	if m.levelMeter {
+		progress += "\n" + levelMeterContent(m.audioLevels, 16)
+	}
+
+	next := bold.Render("UP NEXT") + "\n"
+	upcoming := upcomingSongs(m, nowPlayingUpcoming)
+	if len(upcoming) == 0 {
+		next += subtle.Render("End of the queue")
+	}
+	for i, upcomingSong := range upcoming {
+		line := fmt.Sprintf("%d. %s - %s", i+1, upcomingSong.Title, upcomingSong.Artist)
+		next += strings.TrimRight(LimitString(line, width-4), " ") + "\n"
+	}
+
+	// The upcoming songs are left out when the terminal is too short for them
+	body := strings.Join([]string{top, progress, strings.TrimSuffix(next, "\n")}, "\n\n")
+	if lipgloss.Height(body) > height-3 {
+		body = top + "\n\n" + progress
+	}
+
+	content := lipgloss.NewStyle().Padding(1, 2).MaxHeight(height - 1).Render(body)
+
+	hints := fmt.Sprintf("%s close · %s/%s rate · %s star · %s go to album",
+		strings.Join(api.AppConfig.Keybinds.NowPlaying.Toggle, "/"),
+		strings.Join(api.AppConfig.Keybinds.NowPlaying.RateUp, "/"),
+		strings.Join(api.AppConfig.Keybinds.NowPlaying.RateDown, "/"),
+		strings.Join(api.AppConfig.Keybinds.Favorites.ToggleFavorite, "/"),
+		strings.Join(api.AppConfig.Keybinds.NowPlaying.GoToAlbum, "/"),
+	)
+	footer := subtle.Render(strings.TrimRight(LimitString(hints, width), " "))
+	content = lipgloss.PlaceVertical(height-1, lipgloss.Top, content)
+	content += "\n" + lipgloss.PlaceHorizontal(width, lipgloss.Center, footer)
+
+	// Images of the regular layout are not part of this view
+	content = art.Clear(m.artProtocol, coverSlotFooter) + art.Clear(m.artProtocol, coverSlotDetail) + content
+
+	return activeBorderStyle.
+		Width(width).
+		Height(height).
+		Render(content)
+}
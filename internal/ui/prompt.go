@@ -0,0 +1,135 @@
+package ui
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/MattiaPun/SubTUI/v2/internal/api"
+	"github.com/MattiaPun/SubTUI/v2/internal/playlist"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Helper: Label shown in front of the search bar while a prompt is open
+func promptLabel(prompt int) string {
+	switch prompt {
+	case promptExport:
+		return "Export to: "
+	case promptImport:
+		return "Import from: "
+	case promptSync:
+		return "Sync from: "
+	case promptFilter:
+		return "Filter: "
+	case promptFind:
+		return "Find: "
+	}
+
+	return "Search: "
+}
+
+// Helper: Reuse the search bar to ask for a value
+func openPrompt(m model, prompt int, value string) model {
+	m.prompt = prompt
+	m.focus = focusSearch
+	m.textInput.SetValue(value)
+	m.textInput.CursorEnd()
+	m.textInput.Focus()
+
+	return m
+}
+
+func (m *model) closePrompt() {
+	if m.prompt == promptNone {
+		return
+	}
+
+	m.prompt = promptNone
+	m.promptTarget = ""
+	m.promptName = ""
+	m.textInput.SetValue("")
+}
+
+func cancelPrompt(m model) model {
+	focus := focusMain
+
+	switch m.prompt {
+	case promptFilter:
+		m.clearLiveFilter()
+	case promptFind:
+		m.clearFind()
+		focus = m.findFocus
+	}
+
+	m.closePrompt()
+	m.focus = focus
+	m.textInput.Blur()
+
+	return m
+}
+
+func submitPrompt(m model) (model, tea.Cmd) {
+	// The live filter is already applied, keep it
+	if m.prompt == promptFilter {
+		m.closePrompt()
+		m.focus = focusMain
+		m.textInput.Blur()
+		return m, nil
+	}
+
+	// Keep the matches highlighted to jump between them
+	if m.prompt == promptFind {
+		m.closePrompt()
+		m.focus = m.findFocus
+		m.textInput.Blur()
+		return m, nil
+	}
+
+	value := playlist.ExpandPath(strings.TrimSpace(m.textInput.Value()))
+	prompt, target, name := m.prompt, m.promptTarget, m.promptName
+
+	m = cancelPrompt(m)
+	if value == "" {
+		return m, nil
+	}
+
+	switch prompt {
+	case promptExport:
+		m.loading = true
+		return m, exportPlaylistCmd(value, name, append([]api.Song{}, m.queue...), target)
+
+	case promptImport:
+		m.loading = true
+		return m, importPlaylistCmd(value)
+
+	case promptSync:
+		m.loading = true
+		return m, planSyncCmd(value)
+	}
+
+	return m, nil
+}
+
+// Helper: Export the playlist under the sidebar cursor, otherwise the queue
+func openExportPrompt(m model) model {
+	target, name := "", "Queue"
+
+	if m.focus == focusSidebar {
+		playlistIndex := m.cursorSide - len(albumTypes)
+		if playlistIndex >= 0 && playlistIndex < len(m.playlists) {
+			target = m.playlists[playlistIndex].ID
+			name = m.playlists[playlistIndex].Name
+		}
+	}
+
+	if target == "" && len(m.queue) == 0 {
+		return m
+	}
+
+	fileName := strings.NewReplacer("/", "_", "\\", "_").Replace(name) + ".m3u8"
+
+	m = openPrompt(m, promptExport, filepath.Join("~", fileName))
+	m.promptTarget = target
+	m.promptName = name
+
+	return m
+}
@@ -9,6 +9,11 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 )
 
+const queueHistoryLimit = 50
+
+// Up next songs restored on startup, each one is a request to the server
+const upNextRestoreLimit = 50
+
 func formatDuration(seconds int) string {
 	minutes := seconds / 60
 	secs := seconds % 60
@@ -22,6 +27,12 @@ func (m *model) playQueueIndex(index int, startPaused bool) tea.Cmd {
 
 	m.queueIndex = index
 	song := m.queue[m.queueIndex]
+	upNextID := m.upNextID()
+
+	nextID := ""
+	if nextIndex := m.followingIndex(index); nextIndex != -1 {
+		nextID = m.queue[nextIndex].ID
+	}
 
 	playCmd := func() tea.Msg {
 		err := player.PlaySong(song.ID, startPaused)
@@ -29,17 +40,10 @@ func (m *model) playQueueIndex(index int, startPaused bool) tea.Cmd {
 			return errMsg{err}
 		}
 
-		nextIndex := -1
-		if m.loopMode == LoopOne {
-			nextIndex = index
-		} else if index+1 < len(m.queue) {
-			nextIndex = index + 1
-		} else if m.loopMode == LoopAll && len(m.queue) > 0 {
-			nextIndex = 0
-		}
-
-		if nextIndex != -1 {
-			_ = player.EnqueueSong(m.queue[nextIndex].ID)
+		if upNextID != "" {
+			_ = player.EnqueueSong(upNextID)
+		} else if nextID != "" {
+			_ = player.EnqueueSong(nextID)
 		}
 
 		return nil
@@ -56,17 +60,19 @@ func (m *model) playNext() tea.Cmd {
 		return nil
 	}
 
-	newIndex := m.queueIndex + 1
+	if m.takeUpNext() {
+		return tea.Batch(
+			m.playQueueIndex(m.queueIndex+1, false),
+			saveStateCmd(),
+		)
+	}
 
-	if newIndex >= len(m.queue) {
-		switch m.loopMode {
-		case LoopAll:
-			newIndex = 0
-		case LoopOne:
-			newIndex = m.queueIndex
-		default:
-			return nil
+	newIndex := m.nextPlayable(m.queueIndex, m.loopMode == LoopAll)
+	if newIndex == -1 {
+		if m.loopMode != LoopOne {
+			return m.continueQueue(true)
 		}
+		newIndex = m.queueIndex
 	}
 
 	return m.playQueueIndex(newIndex, false)
@@ -78,21 +84,56 @@ func (m *model) playPrev() tea.Cmd {
 	}
 
 	newIndex := m.queueIndex - 1
+	for newIndex >= 0 && m.queue[newIndex].Filtered {
+		newIndex--
+	}
+
+	// Nothing before it, start the song again
 	if newIndex < 0 {
-		newIndex = 0
+		newIndex = max(m.queueIndex, 0)
 	}
 
 	return m.playQueueIndex(newIndex, false)
 }
 
+// Helper: Index of the first song after index the filter profile lets through, -1 at the end of the queue
+func (m model) nextPlayable(index int, wrap bool) int {
+	for step := 1; step <= len(m.queue); step++ {
+		i := index + step
+		if i >= len(m.queue) {
+			if !wrap {
+				return -1
+			}
+			i %= len(m.queue)
+		}
+
+		if !m.queue[i].Filtered {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// Helper: Index of the song that plays when the one at index ends, -1 if playback stops
+func (m model) followingIndex(index int) int {
+	if m.loopMode == LoopOne {
+		return index
+	}
+
+	return m.nextPlayable(index, m.loopMode == LoopAll)
+}
+
 func (m *model) setQueue(startIndex int) tea.Cmd {
+	return m.setQueueFrom(m.songs, startIndex)
+}
+
+func (m *model) setQueueFrom(songs []api.Song, startIndex int) tea.Cmd {
 	var newQueue []api.Song
 	newStartIndex := 0
 
-	filters := api.AppConfig.Filters
-
-	for i, song := range m.songs {
-		if i == startIndex || !isSongExcluded(*m, song, filters) {
+	for i, song := range songs {
+		if i == startIndex || !isSongExcluded(*m, song) {
 			if i == startIndex {
 				newStartIndex = len(newQueue)
 			}
@@ -101,277 +142,440 @@ func (m *model) setQueue(startIndex int) tea.Cmd {
 		}
 	}
 
+	m.recordQueue()
 	m.queue = newQueue
+	m.queueOriginal = nil
+
+	// Keep shuffle mode on, starting with the selected song
+	if m.shuffled && len(m.queue) > 1 {
+		m.tagQueueEntries(m.queue)
+		m.queueOriginal = append([]api.Song{}, m.queue...)
+		m.queue = m.shuffleWithFirst(m.queue, newStartIndex)
+		newStartIndex = 0
+	}
+
 	return m.playQueueIndex(newStartIndex, false)
 }
 
-func (m *model) savePlayQueue() tea.Cmd {
-	ids := []string{}
-	currentID := ""
-
-	if len(m.queue) != 0 {
-		currentID = m.queue[m.queueIndex].ID
-		for _, song := range m.queue {
-			ids = append(ids, song.ID)
-		}
+// Helper: Save the queue before a mutation so it can be undone
+func (m *model) recordQueue() {
+	m.queueHistory = append(m.queueHistory, m.queueSnapshot())
+	if len(m.queueHistory) > queueHistoryLimit {
+		m.queueHistory = m.queueHistory[len(m.queueHistory)-queueHistoryLimit:]
 	}
 
-	return savePlayQueueCmd(ids, currentID)
+	m.queueFuture = nil
 }
 
-func getSelectedSongs(m model) []api.Song {
-	if m.focus == focusMain && cursorInBounds(m) {
-		switch m.viewMode {
-		case viewList:
-			switch m.displayMode {
-			case displaySongs:
-				return []api.Song{m.songs[m.cursorMain]}
-
-			case displayAlbums:
-				songs, err := api.SubsonicGetAlbum(m.albums[m.cursorMain].ID)
+func (m model) queueSnapshot() queueSnapshot {
+	return queueSnapshot{
+		queue:         append([]api.Song{}, m.queue...),
+		queueIndex:    m.queueIndex,
+		shuffled:      m.shuffled,
+		queueOriginal: append([]api.Song{}, m.queueOriginal...),
+		upNext:        append([]api.Song{}, m.upNext...),
+	}
+}
 
-				if err != nil {
-					return []api.Song{}
-				}
+func (m *model) restoreQueue(snapshot queueSnapshot) tea.Cmd {
+	m.queue = snapshot.queue
+	m.queueIndex = snapshot.queueIndex
+	m.shuffled = snapshot.shuffled
+	m.queueOriginal = snapshot.queueOriginal
+	m.upNext = snapshot.upNext
+	m.syncUpNextState()
+	m.clearSelection()
 
-				songs = applyExclusionFilters(m, songs)
+	if m.viewMode == viewQueue && m.cursorMain >= len(m.queue) {
+		m.cursorMain = max(len(m.queue)-1, 0)
+		m.mainOffset = min(m.mainOffset, m.cursorMain)
+	}
 
-				var filteredSongs []api.Song
-				for _, song := range songs {
+	if len(m.queue) == 0 {
+		m.queueIndex = 0
+		m.syncNextSong()
+		return m.savePlayQueue()
+	}
 
-					if !song.Filtered {
-						filteredSongs = append(filteredSongs, song)
-					}
-				}
+	// Keep the playing song going if it is part of the restored queue
+	if index := playingQueueIndex(*m); index != -1 {
+		m.queueIndex = index
+		m.syncNextSong()
+		return m.savePlayQueue()
+	}
 
-				return filteredSongs
-			}
-		case viewQueue:
-			return []api.Song{m.queue[m.cursorMain]}
-		}
+	if m.queueIndex < 0 || m.queueIndex >= len(m.queue) {
+		m.queueIndex = 0
 	}
 
-	return []api.Song{}
+	return m.playQueueIndex(m.queueIndex, m.playerStatus.Paused)
 }
 
-func (m model) syncNextSong() {
-	if len(m.queue) == 0 {
-		go player.UpdateNextSong("")
-		return
+// Helper: Index of the song MPV is playing, closest to the queue index
+func playingQueueIndex(m model) int {
+	path := m.playerStatus.Path
+	if path == "" || path == "<nil>" {
+		return -1
 	}
 
-	nextIndex := -1
-	switch m.loopMode {
-	case LoopOne:
-		nextIndex = m.queueIndex
-	case LoopNone:
-		if m.queueIndex == len(m.queue)-1 {
-			nextIndex = -1
-		} else {
-			nextIndex = m.queueIndex + 1
+	found := -1
+	for i, song := range m.queue {
+		if !strings.Contains(path, "id="+song.ID) {
+			continue
 		}
-	case LoopAll:
-		if m.queueIndex == len(m.queue)-1 {
-			nextIndex = 0
-		} else {
-			nextIndex = m.queueIndex + 1
+
+		if found == -1 || abs(i-m.queueIndex) < abs(found-m.queueIndex) {
+			found = i
 		}
 	}
 
-	if nextIndex != -1 {
-		go player.UpdateNextSong(m.queue[nextIndex].ID)
-	} else {
-		go player.UpdateNextSong("")
-	}
+	return found
 }
 
-func applyExclusionFilters(m model, songs []api.Song) []api.Song {
-	filters := api.AppConfig.Filters
-	if len(filters.Titles) == 0 && len(filters.Artists) == 0 && len(filters.AlbumArtists) == 0 &&
-		filters.MinDuration == 0 && len(filters.Genres) == 0 && len(filters.Notes) == 0 &&
-		len(filters.Paths) == 0 && filters.MaxPlayCount == 0 && !filters.ExcludeFavorites && filters.MaxRating == 0 {
-		return songs
+func abs(n int) int {
+	if n < 0 {
+		return -n
 	}
 
+	return n
+}
+
+// Helper: Give songs entering the queue a token, so an entry is found in the original order even when a song is queued twice
+func (m *model) tagQueueEntries(songs []api.Song) {
 	for i := range songs {
-		songs[i].Filtered = isSongExcluded(m, songs[i], filters)
+		if songs[i].QueueEntry == 0 {
+			m.queueEntries++
+			songs[i].QueueEntry = m.queueEntries
+		}
 	}
-
-	return songs
 }
 
-func isSongExcluded(m model, song api.Song, filters api.Filters) bool {
-	if isTitleExcluded(song.Title, filters.Titles) {
-		return true
+// Helper: Keep the original order in sync with songs added while shuffled, call it before the songs are added
+func (m *model) addToOriginal(songs []api.Song, next bool) {
+	m.tagQueueEntries(songs)
+
+	if !m.shuffled || len(m.queueOriginal) == 0 {
+		return
 	}
 
-	if isArtistExcluded(song.Artist, filters.Artists) {
-		return true
+	insertAt := len(m.queueOriginal)
+	if next && m.queueIndex < len(m.queue) {
+		if index := findQueueEntry(m.queueOriginal, m.queue[m.queueIndex].QueueEntry); index != -1 {
+			insertAt = index + 1
+		}
 	}
 
-	if isAlbumArtistExcluded(song.AlbumArtists, filters.AlbumArtists) {
-		return true
+	tail := append([]api.Song{}, m.queueOriginal[insertAt:]...)
+	m.queueOriginal = append(append(m.queueOriginal[:insertAt:insertAt], songs...), tail...)
+}
+
+// Helper: Keep the original order in sync with songs removed while shuffled
+func (m *model) removeFromOriginal(songs []api.Song) {
+	if !m.shuffled || len(m.queueOriginal) == 0 {
+		return
 	}
 
-	if isDurationExcluded(song.Duration, filters.MinDuration) {
-		return true
+	original := append([]api.Song{}, m.queueOriginal...)
+	for _, song := range songs {
+		if index := findQueueEntry(original, song.QueueEntry); index != -1 {
+			original = append(original[:index], original[index+1:]...)
+		}
 	}
 
-	if isGenreExcluded(song.Genre, filters.Genres) {
-		return true
+	m.queueOriginal = original
+}
+
+func findQueueEntry(songs []api.Song, entry int) int {
+	if entry == 0 {
+		return -1
 	}
 
-	if isNoteExcluded(song.Note, filters.Notes) {
-		return true
+	for i, song := range songs {
+		if song.QueueEntry == entry {
+			return i
+		}
 	}
 
-	if isPathExcluded(song.Path, filters.Paths) {
-		return true
+	return -1
+}
+
+// Helper: Move the first up next song into the queue after the current song
+func (m *model) takeUpNext() bool {
+	if len(m.upNext) == 0 || len(m.queue) == 0 {
+		return false
 	}
 
-	if isPlayCountExcluded(song.PlayCount, filters.MaxPlayCount) {
-		return true
+	songs := []api.Song{m.upNext[0]}
+	m.upNext = m.upNext[1:]
+	m.syncUpNextState()
+
+	m.addToOriginal(songs, true)
+	song := songs[0]
+
+	insertAt := m.queueIndex + 1
+	tail := append([]api.Song{}, m.queue[insertAt:]...)
+	m.queue = append(append(m.queue[:insertAt:insertAt], song), tail...)
+
+	if m.viewMode == viewQueue && m.cursorMain >= insertAt {
+		m.cursorMain++
 	}
 
-	if isFavoriteExcluded(song.ID, m.starredMap, filters.ExcludeFavorites) {
-		return true
+	return true
+}
+
+// Helper: ID of the up next song MPV should preload, empty if none
+func (m model) upNextID() string {
+	if len(m.upNext) == 0 || m.loopMode == LoopOne {
+		return ""
 	}
 
-	if isRatingExcluded(song.Rating, filters.MaxRating) {
-		return true
+	return m.upNext[0].ID
+}
+
+// Helper: Keep the persisted up next queue in sync, only the first songs are restored on startup
+func (m model) syncUpNextState() {
+	ids := []string{}
+	for _, song := range m.upNext {
+		if len(ids) == upNextRestoreLimit {
+			break
+		}
+		ids = append(ids, song.ID)
 	}
 
-	return false
+	api.AppState.Queue.UpNext = ids
 }
 
-// Helper: Check if title is in the filters
-func isTitleExcluded(title string, filters []string) bool {
-	if len(filters) == 0 || title == "" {
-		return false
+// Helper: Extend the queue with similar songs once it runs out
+func (m *model) continueQueue(play bool) tea.Cmd {
+	if !api.AppConfig.App.AutoContinue || m.continuing || len(m.queue) == 0 {
+		return nil
+	}
+
+	count := api.AppConfig.App.AutoContinueCount
+	if count <= 0 {
+		count = 20
 	}
 
-	for _, f := range filters {
-		if strings.Contains(strings.ToLower(title), strings.ToLower(f)) {
-			return true
+	m.continuing = true
+	return getContinuationSongsCmd(m.queue[len(m.queue)-1], count, play)
+}
+
+func (m *model) savePlayQueue() tea.Cmd {
+	ids := []string{}
+	currentID := ""
+
+	if len(m.queue) != 0 {
+		currentID = m.queue[m.queueIndex].ID
+		for _, song := range m.queue {
+			ids = append(ids, song.ID)
 		}
 	}
 
-	return false
+	return savePlayQueueCmd(ids, currentID)
 }
 
-// Helper: Check if artists is in the filters
-func isArtistExcluded(artist string, filters []string) bool {
-	if len(filters) == 0 || artist == "" {
-		return false
+// Helper: Selected songs, albums are left out since their songs are fetched by selectedAlbumsCmd
+func getSelectedSongs(m model) []api.Song {
+	selectedSongs := []api.Song{}
+
+	if m.focus != focusMain || !cursorInBounds(m) {
+		return selectedSongs
 	}
 
-	for _, f := range filters {
-		if strings.EqualFold(artist, f) {
-			return true
+	for _, i := range selectedIndices(m) {
+		switch m.viewMode {
+		case viewList:
+			if m.displayMode == displaySongs {
+				selectedSongs = append(selectedSongs, m.songs[i])
+			}
+		case viewQueue:
+			selectedSongs = append(selectedSongs, m.queue[i])
 		}
 	}
 
-	return false
+	for _, row := range selectedAllRows(m) {
+		if row.kind == allSong {
+			selectedSongs = append(selectedSongs, m.allResults.Songs[row.index])
+		}
+	}
+
+	return selectedSongs
 }
 
-// Helper: Check if album artists is in the filters
-func isAlbumArtistExcluded(albumArtists []api.Artist, filters []string) bool {
-	if len(filters) == 0 || len(albumArtists) == 0 {
-		return false
+// Helper: IDs of the selected albums
+func getSelectedAlbumIDs(m model) []string {
+	ids := []string{}
+
+	if m.focus != focusMain || m.viewMode != viewList || !cursorInBounds(m) {
+		return ids
 	}
 
-	for _, f := range filters {
-		for _, artist := range albumArtists {
-			if strings.EqualFold(artist.Name, f) {
-				return true
-			}
+	if m.displayMode == displayAlbums {
+		for _, i := range selectedIndices(m) {
+			ids = append(ids, m.albums[i].ID)
 		}
 	}
 
-	return false
+	for _, row := range selectedAllRows(m) {
+		if row.kind == allAlbum {
+			ids = append(ids, m.allResults.Albums[row.index].ID)
+		}
+	}
+
+	return ids
 }
 
-// Helper: Check if duration is below filter
-func isDurationExcluded(duration int, minDuration int) bool {
-	if minDuration <= 0 {
-		return false
+// Helper: Run an action on the selection, selected albums are fetched in the background first
+func withSelectedSongs(m model, action int, playlistID string) (model, tea.Cmd) {
+	if ids := getSelectedAlbumIDs(m); len(ids) > 0 {
+		songs := getSelectedSongs(m)
+		m.clearSelection()
+		return m, selectedAlbumsCmd(songs, ids, action, playlistID)
 	}
 
-	return duration <= minDuration
+	return m.applySelection(selectedSongsMsg{songs: getSelectedSongs(m), action: action, playlistID: playlistID})
 }
 
-// Helper: Check if genre is in the filters
-func isGenreExcluded(genre string, filters []string) bool {
-	if len(filters) == 0 || genre == "" {
-		return false
+func (m model) applySelection(msg selectedSongsMsg) (model, tea.Cmd) {
+	m.clearSelection()
+
+	if len(msg.songs) == 0 {
+		return m, nil
 	}
 
-	for _, f := range filters {
-		if strings.EqualFold(genre, f) {
-			return true
+	switch msg.action {
+	case selectionQueueNext:
+		m.recordQueue()
+
+		if len(m.queue) == 0 {
+			m.queue = msg.songs
+			m.queueIndex = 0
+		} else {
+			// Up next plays in the order it was added, before the rest of the queue
+			m.upNext = append(m.upNext, msg.songs...)
+			m.syncUpNextState()
 		}
-	}
 
-	return false
-}
+		// Sync MPV's Queue
+		m.syncNextSong()
 
-func isNoteExcluded(note string, filters []string) bool {
-	if len(filters) == 0 || note == "" {
-		return false
+		return m, saveStateCmd()
+
+	case selectionQueueLast:
+		m.recordQueue()
+		m.addToOriginal(msg.songs, false)
+		m.queue = append(m.queue, msg.songs...)
+
+		// Sync MPV's Queue
+		m.syncNextSong()
+
+	case selectionPlaylist:
+		songIDs := []string{}
+		for _, song := range msg.songs {
+			songIDs = append(songIDs, song.ID)
+		}
+
+		return m, addSongsToPlaylistCmd(songIDs, msg.playlistID)
 	}
 
-	for _, f := range filters {
-		if strings.Contains(strings.ToLower(note), strings.ToLower(f)) {
-			return true
+	return m, nil
+}
+
+func (m model) handleSelectedSongs(msg selectedSongsMsg) (tea.Model, tea.Cmd) {
+	// Album songs get the same filtering as an opened album
+	songs := []api.Song{}
+	for _, song := range applyExclusionFilters(m, msg.songs) {
+		if !song.Filtered {
+			songs = append(songs, song)
 		}
 	}
+	msg.songs = songs
 
-	return false
+	return m.applySelection(msg)
 }
 
-// Helper: Check if path is in the filters
-func isPathExcluded(path string, filters []string) bool {
-	if len(filters) == 0 || path == "" {
-		return false
+func (m model) syncNextSong() {
+	if len(m.queue) == 0 {
+		go player.UpdateNextSong("")
+		return
 	}
 
-	for _, f := range filters {
-		if strings.Contains(strings.ToLower(path), strings.ToLower(f)) {
-			return true
-		}
+	if id := m.upNextID(); id != "" {
+		go player.UpdateNextSong(id)
+		return
 	}
 
-	return false
+	if nextIndex := m.followingIndex(m.queueIndex); nextIndex != -1 {
+		go player.UpdateNextSong(m.queue[nextIndex].ID)
+	} else {
+		go player.UpdateNextSong("")
+	}
 }
 
-// Helper: Check if playcount is below filters
-func isPlayCountExcluded(playCount int, maxPlayCount int) bool {
-	if maxPlayCount == 0 {
-		return false
+// Helper: Resolve the replaygain mode, 'auto' picks album gain inside a contiguous album
+func (m model) replayGainMode() string {
+	mode := strings.ToLower(api.AppConfig.App.ReplayGain)
+	if mode != "auto" {
+		return mode
 	}
 
-	return playCount <= maxPlayCount
+	if isContiguousAlbum(m.queue, m.queueIndex) {
+		return "album"
+	}
+
+	return "track"
 }
 
-// Helper: Check if favorites get filtered
-func isFavoriteExcluded(songID string, starredMap map[string]bool, excludeFavorites bool) bool {
-	if !excludeFavorites {
+// Helper: Check if the song at index is played in album order with a neighbouring song
+func isContiguousAlbum(queue []api.Song, index int) bool {
+	if index < 0 || index >= len(queue) || queue[index].AlbumID == "" {
 		return false
 	}
 
-	isStarred := starredMap[songID]
-	return isStarred
-}
+	song := queue[index]
 
-// Helper: Check if rating is below filters
-func isRatingExcluded(rating int, maxRating int) bool {
-	if maxRating <= 0 || maxRating > 5 {
-		return false
+	if index > 0 && queue[index-1].AlbumID == song.AlbumID && isTrackBefore(queue[index-1], song) {
+		return true
 	}
 
-	if rating > 0 && rating <= maxRating {
+	if index+1 < len(queue) && queue[index+1].AlbumID == song.AlbumID && isTrackBefore(song, queue[index+1]) {
 		return true
 	}
 
 	return false
 }
+
+// Helper: Check if a comes before b in album order
+func isTrackBefore(a api.Song, b api.Song) bool {
+	if a.DiscNumber != b.DiscNumber {
+		return a.DiscNumber < b.DiscNumber
+	}
+
+	return a.TrackNumber < b.TrackNumber
+}
+
+// Helper: Check if the server reports ReplayGain values for a song
+func hasReplayGain(song api.Song) bool {
+	gain := song.ReplayGain
+	return gain.TrackGain != 0 || gain.AlbumGain != 0 || gain.TrackPeak != 0 || gain.AlbumPeak != 0
+}
+
+// Helper: Pick the replaygain mode and the loudness fallback for the song that started,
+// mpv is only told when either changes so gapless transitions stay untouched
+func (m model) syncAudioNormalization(song api.Song) {
+	mode := m.replayGainMode()
+
+	if strings.ToLower(api.AppConfig.App.ReplayGain) == "auto" {
+		player.SetReplayGain(mode)
+	}
+
+	player.SetLoudnessNormalization(mode == "no" || !hasReplayGain(song))
+}
+
+func applyExclusionFilters(m model, songs []api.Song) []api.Song {
+	for i := range songs {
+		songs[i].Filtered = isSongExcluded(m, songs[i])
+	}
+
+	return songs
+}
@@ -0,0 +1,149 @@
+package ui
+
+import (
+	"fmt"
+
+	"github.com/MattiaPun/SubTUI/v2/internal/api"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const searchHistoryLimit = 50
+
+// Names of the filter modes in state.toml, indexed by filter mode
+var searchModes = []string{"songs", "albums", "artists", "all"}
+
+func searchModeName(mode int) string {
+	if mode < 0 || mode >= len(searchModes) {
+		return searchModes[filterSongs]
+	}
+
+	return searchModes[mode]
+}
+
+func searchModeFromName(name string) int {
+	for mode, modeName := range searchModes {
+		if modeName == name {
+			return mode
+		}
+	}
+
+	return filterSongs
+}
+
+// Helper: Label of a filter mode as shown in the header
+func filterModeLabel(mode int) string {
+	switch mode {
+	case filterSongs:
+		return "Songs"
+	case filterAlbums:
+		return "Albums"
+	case filterArtist:
+		return "Artist"
+	case filterAll:
+		return "All"
+	}
+
+	return ""
+}
+
+func searchTitle(query string, mode int) string {
+	return fmt.Sprintf("%s \"%s\"", filterModeLabel(mode), query)
+}
+
+// Helper: Remember a submitted query for its filter mode, newest first
+func recordSearch(query string, mode int) {
+	key := searchModeName(mode)
+
+	history := []string{query}
+	for _, previous := range api.AppState.Search.History[key] {
+		if previous != query {
+			history = append(history, previous)
+		}
+	}
+
+	if len(history) > searchHistoryLimit {
+		history = history[:searchHistoryLimit]
+	}
+
+	if api.AppState.Search.History == nil {
+		api.AppState.Search.History = make(map[string][]string)
+	}
+	api.AppState.Search.History[key] = history
+}
+
+// Helper: Step through the search history of the current filter mode, 0 is the typed text
+func recallSearch(m model, older bool) model {
+	history := api.AppState.Search.History[searchModeName(m.filterMode)]
+
+	pos := m.historyPos - 1
+	if older {
+		pos = m.historyPos + 1
+	}
+
+	if pos < 0 || pos > len(history) {
+		return m
+	}
+
+	if m.historyPos == 0 {
+		m.historyDraft = m.textInput.Value()
+	}
+	m.historyPos = pos
+
+	value := m.historyDraft
+	if pos > 0 {
+		value = history[pos-1]
+	}
+
+	m.textInput.SetValue(value)
+	m.textInput.CursorEnd()
+
+	return m
+}
+
+// Helper: Index of the saved search under the sidebar cursor, -1 if none
+func savedSearchIndex(m model) int {
+	index := m.cursorSide - len(albumTypes) - len(m.playlists) - len(api.AppConfig.SmartPlaylists)
+	if index < 0 || index >= len(api.AppState.Search.Saved) {
+		return -1
+	}
+
+	return index
+}
+
+func savedSearchName(search api.SavedSearch) string {
+	return fmt.Sprintf("%s (%s)", search.Query, filterModeLabel(searchModeFromName(search.Mode)))
+}
+
+// Helper: Pin the last search to the sidebar, or unpin the saved search under the cursor
+func toggleSavedSearch(m model) (model, tea.Cmd) {
+	saved := api.AppState.Search.Saved
+
+	if index := savedSearchIndex(m); index != -1 && m.focus == focusSidebar {
+		api.AppState.Search.Saved = append(saved[:index:index], saved[index+1:]...)
+		m.cursorSide = min(m.cursorSide, sidebarLen(m)-1)
+
+		return m, saveStateCmd()
+	}
+
+	if m.lastSearchQuery == "" {
+		return m, nil
+	}
+
+	search := api.SavedSearch{Query: m.lastSearchQuery, Mode: searchModeName(m.lastSearchMode)}
+	for _, existing := range saved {
+		if existing == search {
+			return m, nil
+		}
+	}
+
+	api.AppState.Search.Saved = append(saved, search)
+	return m, saveStateCmd()
+}
+
+func openSavedSearch(m model, index int) (model, tea.Cmd) {
+	search := api.AppState.Search.Saved[index]
+	m.focus = focusMain
+	m.viewTyped = false
+
+	return searchIn(m, search.Query, searchModeFromName(search.Mode))
+}
@@ -0,0 +1,188 @@
+package ui
+
+import (
+	"sort"
+)
+
+// Helper: Length of the list shown in the main view
+func mainListLen(m model) int {
+	if m.viewMode == viewQueue {
+		return len(m.queue)
+	}
+
+	switch m.displayMode {
+	case displaySongs:
+		return len(m.songs)
+	case displayAlbums:
+		return len(m.albums)
+	case displayArtist:
+		return len(m.artists)
+	case displayAll:
+		return len(allRows(m))
+	}
+
+	return 0
+}
+
+// Helper: Check if a row is marked or inside the visual range
+func isRowSelected(m model, index int) bool {
+	if m.selection[index] {
+		return true
+	}
+
+	if m.visualMode {
+		low, high := m.visualAnchor, m.cursorMain
+		if low > high {
+			low, high = high, low
+		}
+
+		return index >= low && index <= high
+	}
+
+	return false
+}
+
+// Helper: Check if any row is marked or inside the visual range
+func hasSelection(m model) bool {
+	return m.visualMode || len(m.selection) > 0
+}
+
+// Helper: Sorted indices of the selected rows, falls back to the cursor row
+func selectedIndices(m model) []int {
+	listLen := mainListLen(m)
+	indices := []int{}
+
+	for i := 0; i < listLen; i++ {
+		if isRowSelected(m, i) && !isHiddenQueueRow(m, i) {
+			indices = append(indices, i)
+		}
+	}
+
+	if len(indices) == 0 && m.cursorMain >= 0 && m.cursorMain < listLen {
+		indices = append(indices, m.cursorMain)
+	}
+
+	sort.Ints(indices)
+	return indices
+}
+
+// Helper: Turn the visual range into marked rows
+func (m *model) commitVisual() {
+	if !m.visualMode {
+		return
+	}
+
+	for _, i := range selectedIndices(*m) {
+		m.selection[i] = true
+	}
+
+	m.visualMode = false
+}
+
+func (m *model) clearSelection() {
+	m.selection = make(map[int]bool)
+	m.visualMode = false
+	m.visualAnchor = 0
+}
+
+func toggleSelect(m model) model {
+	if m.focus != focusMain || mainListLen(m) == 0 {
+		return m
+	}
+
+	if m.selection[m.cursorMain] {
+		delete(m.selection, m.cursorMain)
+	} else {
+		m.selection[m.cursorMain] = true
+	}
+
+	return m
+}
+
+func toggleVisualMode(m model) model {
+	if m.focus != focusMain || mainListLen(m) == 0 {
+		return m
+	}
+
+	if m.visualMode {
+		m.commitVisual()
+	} else {
+		m.visualMode = true
+		m.visualAnchor = m.cursorMain
+	}
+
+	return m
+}
+
+// Items under the cursor and in the selection, by ID, so they can be found again after the list was rebuilt
+type listItems struct {
+	cursor   string
+	anchor   string
+	selected map[string]bool
+}
+
+// Helper: ID of a row of the song, album or artist list
+func mainListID(m model, i int) string {
+	if m.viewMode != viewList || i < 0 {
+		return ""
+	}
+
+	switch {
+	case m.displayMode == displaySongs && i < len(m.songs):
+		return m.songs[i].ID
+	case m.displayMode == displayAlbums && i < len(m.albums):
+		return m.albums[i].ID
+	case m.displayMode == displayArtist && i < len(m.artists):
+		return m.artists[i].ID
+	}
+
+	return ""
+}
+
+// Helper: Remember the items under the cursor and in the selection before the list changes
+func keepListItems(m model) listItems {
+	items := listItems{
+		cursor:   mainListID(m, m.cursorMain),
+		selected: make(map[string]bool),
+	}
+
+	if m.visualMode {
+		items.anchor = mainListID(m, m.visualAnchor)
+	}
+
+	for i := range m.selection {
+		if id := mainListID(m, i); id != "" {
+			items.selected[id] = true
+		}
+	}
+
+	return items
+}
+
+// Helper: Move the cursor and selection back onto the same items after a page was added and the list sorted again
+func (m *model) restoreListItems(items listItems) {
+	m.selection = make(map[int]bool)
+
+	for i := 0; i < mainListLen(*m); i++ {
+		id := mainListID(*m, i)
+		if id == "" {
+			continue
+		}
+
+		if id == items.cursor {
+			m.cursorMain = i
+		}
+		if id == items.anchor {
+			m.visualAnchor = i
+		}
+		if items.selected[id] {
+			m.selection[i] = true
+		}
+	}
+
+	// Height - Search(3) - Footer(6) - Margins(4) - TableHeader(2) = 17
+	visibleRows := max(m.height-17-upNextHeight(*m), 1)
+	if m.cursorMain < m.mainOffset || m.cursorMain >= m.mainOffset+visibleRows {
+		m.mainOffset = max(m.cursorMain-2, 0)
+	}
+}
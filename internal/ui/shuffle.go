@@ -0,0 +1,140 @@
+package ui
+
+import (
+	"math"
+	"math/rand"
+	"slices"
+	"strings"
+
+	"github.com/MattiaPun/SubTUI/v2/internal/api"
+)
+
+const (
+	recentlyPlayedLimit = 200
+	sameArtistPenalty   = 0.05
+	sameAlbumPenalty    = 0.05
+)
+
+// Helper: Shuffle songs into a new slice using the configured strategy
+func (m model) shuffleSongs(songs []api.Song, prev *api.Song) []api.Song {
+	if strings.ToLower(api.AppConfig.App.Shuffle) == "smart" {
+		return smartShuffle(songs, prev, m.starredMap, m.recentPlays)
+	}
+
+	shuffled := append([]api.Song{}, songs...)
+
+	rand.Shuffle(len(shuffled), func(i, j int) {
+		shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
+	})
+
+	return shuffled
+}
+
+// Helper: Shuffle songs into a new slice with the song at index first
+func (m model) shuffleWithFirst(songs []api.Song, index int) []api.Song {
+	if index < 0 || index >= len(songs) {
+		return m.shuffleSongs(songs, nil)
+	}
+
+	first := songs[index]
+	rest := append(append([]api.Song{}, songs[:index]...), songs[index+1:]...)
+	return append([]api.Song{first}, m.shuffleSongs(rest, &first)...)
+}
+
+// Weighted shuffle that spreads artists and albums and plays recent songs later
+func smartShuffle(songs []api.Song, prev *api.Song, starredMap map[string]bool, recentlyPlayed []string) []api.Song {
+	recency := make(map[string]float64)
+	for i, id := range recentlyPlayed {
+		// Most recent play is last, weighs the heaviest
+		recency[id] = float64(i+1) / float64(len(recentlyPlayed))
+	}
+
+	remaining := append([]api.Song{}, songs...)
+	weights := make([]float64, len(remaining))
+	for i, song := range remaining {
+		weights[i] = songWeight(song, starredMap, recency[song.ID])
+	}
+
+	shuffled := make([]api.Song, 0, len(songs))
+	for len(remaining) > 0 {
+		total := 0.0
+		adjusted := make([]float64, len(remaining))
+		for i, song := range remaining {
+			adjusted[i] = weights[i]
+
+			if prev != nil {
+				if song.Artist != "" && strings.EqualFold(song.Artist, prev.Artist) {
+					adjusted[i] *= sameArtistPenalty
+				}
+				if song.AlbumID != "" && song.AlbumID == prev.AlbumID {
+					adjusted[i] *= sameAlbumPenalty
+				}
+			}
+
+			total += adjusted[i]
+		}
+
+		pick := len(remaining) - 1
+		target := rand.Float64() * total
+		for i, weight := range adjusted {
+			target -= weight
+			if target < 0 {
+				pick = i
+				break
+			}
+		}
+
+		picked := remaining[pick]
+		shuffled = append(shuffled, picked)
+		prev = &picked
+
+		remaining = append(remaining[:pick], remaining[pick+1:]...)
+		weights = append(weights[:pick], weights[pick+1:]...)
+	}
+
+	return shuffled
+}
+
+// Helper: Base weight of a song for the smart shuffle
+func songWeight(song api.Song, starredMap map[string]bool, recency float64) float64 {
+	weight := 1.0
+
+	// Songs played often or recently come later
+	weight /= 1 + math.Log1p(float64(song.PlayCount))/4
+	weight *= 1 - 0.9*recency
+
+	if api.AppConfig.App.ShuffleFavorRated {
+		weight *= 1 + float64(song.Rating)/4
+		if starredMap[song.ID] {
+			weight *= 1.5
+		}
+	}
+
+	return weight
+}
+
+// Helper: Songs started last according to the play history, most recent last
+func recentPlays(events []api.HistoryEvent) []string {
+	var recent []string
+	for _, event := range events {
+		if event.Type == api.HistoryStart {
+			recent = addRecentPlay(recent, event.SongID)
+		}
+	}
+
+	return recent
+}
+
+// Helper: Move a started song to the end of the recent plays
+func addRecentPlay(recent []string, id string) []string {
+	if index := slices.Index(recent, id); index != -1 {
+		recent = append(recent[:index:index], recent[index+1:]...)
+	}
+
+	recent = append(recent, id)
+	if len(recent) > recentlyPlayedLimit {
+		recent = recent[len(recent)-recentlyPlayedLimit:]
+	}
+
+	return recent
+}
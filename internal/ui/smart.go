@@ -0,0 +1,275 @@
+package ui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/MattiaPun/SubTUI/v2/internal/api"
+	"github.com/MattiaPun/SubTUI/v2/internal/query"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	smartPageSize   = 150
+	smartRandomSize = 500
+)
+
+// Helper: Number of items in the sidebar
+func sidebarLen(m model) int {
+	return len(albumTypes) + len(m.playlists) + len(api.AppConfig.SmartPlaylists) + len(api.AppState.Search.Saved)
+}
+
+// Helper: Name of a sidebar item
+func sidebarName(m model, i int) string {
+	if i < len(albumTypes) {
+		return albumTypes[i]
+	} else if i < len(albumTypes)+len(m.playlists) {
+		return m.playlists[i-len(albumTypes)].Name
+	} else if i < len(albumTypes)+len(m.playlists)+len(api.AppConfig.SmartPlaylists) {
+		return api.AppConfig.SmartPlaylists[i-len(albumTypes)-len(m.playlists)].Name
+	} else if i < sidebarLen(m) {
+		return savedSearchName(api.AppState.Search.Saved[i-len(albumTypes)-len(m.playlists)-len(api.AppConfig.SmartPlaylists)])
+	}
+
+	return ""
+}
+
+// Helper: Index of the smart playlist under the sidebar cursor, -1 if none
+func smartPlaylistIndex(m model) int {
+	index := m.cursorSide - len(albumTypes) - len(m.playlists)
+	if index < 0 || index >= len(api.AppConfig.SmartPlaylists) {
+		return -1
+	}
+
+	return index
+}
+
+// Helper: Compile the rules of a smart playlist into one query
+func smartPlaylistQuery(rules api.SmartPlaylist) (query.Expr, error) {
+	var terms []query.Expr
+
+	anyOf := func(field string, op string, values []string) error {
+		var options []query.Expr
+		for _, value := range values {
+			term, err := query.Term(field, op, value)
+			if err != nil {
+				return err
+			}
+			options = append(options, term)
+		}
+
+		if len(options) > 0 {
+			terms = append(terms, query.Or(options...))
+		}
+		return nil
+	}
+
+	between := func(field string, low int, high int) {
+		if low <= 0 && high <= 0 {
+			return
+		}
+
+		bounds := ""
+		if low > 0 {
+			bounds = strconv.Itoa(low)
+		}
+		bounds += ".."
+		if high > 0 {
+			bounds += strconv.Itoa(high)
+		}
+
+		term, _ := query.Term(field, ":", bounds)
+		terms = append(terms, term)
+	}
+
+	if err := anyOf("genre", "=", rules.Genres); err != nil {
+		return nil, err
+	}
+	if err := anyOf("artist", "=", rules.Artists); err != nil {
+		return nil, err
+	}
+	if err := anyOf("path", ":", rules.Paths); err != nil {
+		return nil, err
+	}
+
+	between("year", rules.MinYear, rules.MaxYear)
+	between("rating", rules.MinRating, 0)
+	between("played", rules.MinPlayCount, rules.MaxPlayCount)
+	between("duration", rules.MinDuration, rules.MaxDuration)
+
+	if rules.Starred {
+		term, _ := query.Term("starred", ":", "true")
+		terms = append(terms, term)
+	}
+
+	expr, err := query.Parse(rules.Query)
+	if err != nil {
+		return nil, fmt.Errorf("smart playlist %s: %v", rules.Name, err)
+	}
+	if expr != nil {
+		terms = append(terms, expr)
+	}
+
+	return query.And(terms...), nil
+}
+
+// Helper: Songs of a smart playlist, paging the source only until the limit is reached
+func smartPlaylistSongs(rules api.SmartPlaylist, match func(api.Song) bool) ([]api.Song, error) {
+	matched := []api.Song{}
+	seen := make(map[string]bool)
+
+	// Add the matching songs of a page, true once the limit is reached
+	add := func(page []api.Song) bool {
+		for _, song := range page {
+			if rules.Limit > 0 && len(matched) >= rules.Limit {
+				return true
+			}
+
+			if !seen[song.ID] && match(song) {
+				seen[song.ID] = true
+				matched = append(matched, song)
+			}
+		}
+
+		return rules.Limit > 0 && len(matched) >= rules.Limit
+	}
+
+	switch strings.ToLower(rules.Source) {
+	case "starred":
+		result, err := api.SubsonicGetStarred()
+		if err != nil {
+			return nil, err
+		}
+		add(result.Songs)
+		return matched, nil
+
+	case "random":
+		genre := ""
+		if len(rules.Genres) == 1 {
+			genre = rules.Genres[0]
+		}
+		songs, err := api.SubsonicGetRandomSongs(smartRandomSize, genre, rules.MinYear, rules.MaxYear)
+		if err != nil {
+			return nil, err
+		}
+		add(songs)
+		return matched, nil
+	}
+
+	// Page through the songs of the wanted genres, or the whole library without a genre rule
+	pagers := []func(offset int) ([]api.Song, error){
+		func(offset int) ([]api.Song, error) { return api.SubsonicSearchSong("", offset) },
+	}
+
+	if len(rules.Genres) > 0 {
+		genres, err := smartPlaylistGenres(rules.Genres)
+		if err != nil {
+			return nil, err
+		}
+
+		pagers = nil
+		for _, genre := range genres {
+			pagers = append(pagers, func(offset int) ([]api.Song, error) {
+				return api.SubsonicGetSongsByGenre(genre, offset)
+			})
+		}
+	}
+
+	for _, pager := range pagers {
+		for offset := 0; ; offset += smartPageSize {
+			page, err := pager(offset)
+			if err != nil {
+				return nil, err
+			}
+
+			if add(page) {
+				return matched, nil
+			}
+			if len(page) < smartPageSize {
+				break
+			}
+		}
+	}
+
+	return matched, nil
+}
+
+// Helper: Server spelling of the genres of a rule, which match case insensitively
+func smartPlaylistGenres(wanted []string) ([]string, error) {
+	genres, err := api.SubsonicGetGenres()
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, genre := range genres {
+		for _, name := range wanted {
+			if strings.EqualFold(genre.Name, name) {
+				names = append(names, genre.Name)
+				break
+			}
+		}
+	}
+
+	return names, nil
+}
+
+func getSmartPlaylistCmd(rules api.SmartPlaylist, shuffled bool) tea.Cmd {
+	return func() tea.Msg {
+		expr, err := smartPlaylistQuery(rules)
+		if err != nil {
+			return errMsg{err}
+		}
+
+		starred := make(map[string]bool)
+		if rules.Starred || rules.Query != "" {
+			result, err := api.SubsonicGetStarred()
+			if err != nil {
+				return errMsg{err}
+			}
+
+			for _, song := range result.Songs {
+				starred[song.ID] = true
+			}
+		}
+
+		matched, err := smartPlaylistSongs(rules, func(song api.Song) bool {
+			return expr.Match(query.SongFields(song, starred[song.ID]))
+		})
+		if err != nil {
+			return errMsg{err}
+		}
+
+		return smartPlaylistMsg{name: rules.Name, songs: matched, shuffled: shuffled}
+	}
+}
+
+// Helper: Open a smart playlist, from the cache if it was built before
+func openSmartPlaylist(m model, index int, shuffled bool) (model, tea.Cmd) {
+	rules := api.AppConfig.SmartPlaylists[index]
+
+	m.pushView(rules.Name)
+	m.loading = true
+	m.focus = focusMain
+	m.viewMode = viewList
+	m.displayMode = displaySongs
+
+	if songs, ok := m.smartSongs[rules.Name]; ok {
+		return m.requestList(func() tea.Msg {
+			return smartPlaylistMsg{name: rules.Name, songs: songs, shuffled: shuffled}
+		})
+	}
+
+	return m.requestList(getSmartPlaylistCmd(rules, shuffled))
+}
+
+func refreshSmartPlaylists(m model) (model, tea.Cmd) {
+	m.smartSongs = make(map[string][]api.Song)
+
+	if index := smartPlaylistIndex(m); index != -1 && m.focus == focusSidebar {
+		return openSmartPlaylist(m, index, false)
+	}
+
+	return m, nil
+}
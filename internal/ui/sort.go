@@ -0,0 +1,232 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/MattiaPun/SubTUI/v2/internal/api"
+	zone "github.com/lrstanley/bubblezone"
+)
+
+const (
+	sortNone = iota
+	sortTitle
+	sortArtist
+	sortAlbum
+	sortYear
+	sortTrack
+	sortGenre
+	sortRating
+	sortPlays
+	sortDuration
+)
+
+type sortState struct {
+	column int
+	desc   bool
+}
+
+// Helper: Columns the shown list can be sorted by, in the order they are cycled
+func sortColumns(m model) []int {
+	switch m.displayMode {
+	case displayAlbums:
+		return []int{sortNone, sortAlbum, sortArtist, sortDuration}
+	case displayArtist:
+		return []int{sortNone, sortArtist}
+	case displayAll:
+		return nil
+	}
+
+	cols := api.AppConfig.Columns
+	columns := []int{sortNone}
+
+	if cols.ShowTrackNumber {
+		columns = append(columns, sortTrack)
+	}
+	if cols.ShowTitle {
+		columns = append(columns, sortTitle)
+	}
+	if cols.ShowArtist {
+		columns = append(columns, sortArtist)
+	}
+	if cols.ShowAlbum {
+		columns = append(columns, sortAlbum)
+	}
+	if cols.ShowYear {
+		columns = append(columns, sortYear)
+	}
+	if cols.ShowGenre {
+		columns = append(columns, sortGenre)
+	}
+	if cols.ShowRating {
+		columns = append(columns, sortRating)
+	}
+	if cols.ShowPlayCount {
+		columns = append(columns, sortPlays)
+	}
+	if cols.ShowDuration {
+		columns = append(columns, sortDuration)
+	}
+
+	return columns
+}
+
+// Helper: Check if any of the lists is sorted
+func (m model) isSorted() bool {
+	for _, state := range m.sorts {
+		if state.column != sortNone {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Helper: Column header with the sort arrow in front, clickable when sorting is possible
+func sortLabel(label string, width int, column int, state sortState) string {
+	if state.column == column && column != sortNone {
+		if state.desc {
+			label = "▼" + label
+		} else {
+			label = "▲" + label
+		}
+	}
+
+	return zone.Mark(fmt.Sprintf("sort_%d", column), LimitString(label, width))
+}
+
+// Helper: Switch to the next sort column of the shown list
+func cycleSort(m model) model {
+	if m.viewMode != viewList || m.displayMode == displayAll {
+		return m
+	}
+
+	columns := sortColumns(m)
+	state := m.sorts[m.displayMode]
+
+	index := 0
+	for i, column := range columns {
+		if column == state.column {
+			index = i
+		}
+	}
+
+	return setSort(m, sortState{column: columns[(index+1)%len(columns)]})
+}
+
+func reverseSort(m model) model {
+	if m.viewMode != viewList || m.displayMode == displayAll {
+		return m
+	}
+
+	state := m.sorts[m.displayMode]
+	if state.column == sortNone {
+		return m
+	}
+
+	state.desc = !state.desc
+	return setSort(m, state)
+}
+
+// Helper: Sort by a clicked column, clicking it again reverses the order
+func sortByColumn(m model, column int) model {
+	state := m.sorts[m.displayMode]
+	if state.column == column {
+		state.desc = !state.desc
+	} else {
+		state = sortState{column: column}
+	}
+
+	return setSort(m, state)
+}
+
+func setSort(m model, state sortState) model {
+	m.sorts[m.displayMode] = state
+	m.clearSelection()
+	m.filterLists()
+
+	return m
+}
+
+func compareText(a string, b string) int {
+	return strings.Compare(strings.ToLower(a), strings.ToLower(b))
+}
+
+func sortSongs(songs []api.Song, state sortState) {
+	if state.column == sortNone {
+		return
+	}
+
+	compare := func(a api.Song, b api.Song) int {
+		switch state.column {
+		case sortTitle:
+			return compareText(a.Title, b.Title)
+		case sortArtist:
+			return compareText(a.Artist, b.Artist)
+		case sortAlbum:
+			return compareText(a.Album, b.Album)
+		case sortYear:
+			return a.Year - b.Year
+		case sortTrack:
+			if a.DiscNumber != b.DiscNumber {
+				return a.DiscNumber - b.DiscNumber
+			}
+			return a.TrackNumber - b.TrackNumber
+		case sortGenre:
+			return compareText(a.Genre, b.Genre)
+		case sortRating:
+			return a.Rating - b.Rating
+		case sortPlays:
+			return a.PlayCount - b.PlayCount
+		case sortDuration:
+			return a.Duration - b.Duration
+		}
+		return 0
+	}
+
+	sort.SliceStable(songs, func(i, j int) bool {
+		if state.desc {
+			return compare(songs[j], songs[i]) < 0
+		}
+		return compare(songs[i], songs[j]) < 0
+	})
+}
+
+func sortAlbums(albums []api.Album, state sortState) {
+	if state.column == sortNone {
+		return
+	}
+
+	compare := func(a api.Album, b api.Album) int {
+		switch state.column {
+		case sortAlbum:
+			return compareText(a.Name, b.Name)
+		case sortArtist:
+			return compareText(a.Artist, b.Artist)
+		case sortDuration:
+			return int(a.Duration - b.Duration)
+		}
+		return 0
+	}
+
+	sort.SliceStable(albums, func(i, j int) bool {
+		if state.desc {
+			return compare(albums[j], albums[i]) < 0
+		}
+		return compare(albums[i], albums[j]) < 0
+	})
+}
+
+func sortArtists(artists []api.Artist, state sortState) {
+	if state.column != sortArtist {
+		return
+	}
+
+	sort.SliceStable(artists, func(i, j int) bool {
+		if state.desc {
+			return compareText(artists[j].Name, artists[i].Name) < 0
+		}
+		return compareText(artists[i].Name, artists[j].Name) < 0
+	})
+}
@@ -14,16 +14,27 @@ const (
 	viewLogin = 99
 )
 
+const (
+	promptNone = iota
+	promptExport
+	promptImport
+	promptSync
+	promptFilter
+	promptFind
+)
+
 const (
 	filterSongs = iota
 	filterAlbums
 	filterArtist
+	filterAll
 )
 
 const (
 	displaySongs = iota
 	displayAlbums
 	displayArtist
+	displayAll
 )
 
 const (
@@ -6,6 +6,18 @@ import (
 )
 
 func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	updated, cmd := m.handleMsg(msg)
+
+	// The album list shows the cover of the album under the cursor
+	if next, ok := updated.(model); ok {
+		next, coverCmd := next.requestAlbumCover()
+		return next, tea.Batch(cmd, coverCmd)
+	}
+
+	return updated, cmd
+}
+
+func (m model) handleMsg(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
 
 	switch msg := msg.(type) {
@@ -31,6 +43,9 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case statusMsg:
 		return m.handleStatus(msg)
 
+	case audioLevelsMsg:
+		return m.handleAudioLevels(msg)
+
 	case songsResultMsg:
 		return m.handleSongResult(msg)
 
@@ -40,6 +55,45 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case artistsResultMsg:
 		return m.handleArtistsResult(msg)
 
+	case allResultMsg:
+		return m.handleAllResult(msg)
+
+	case coverMsg:
+		return m.handleCover(msg)
+
+	case searchDebounceMsg:
+		return m.handleSearchDebounce(msg)
+
+	case radioResultMsg:
+		return m.handleRadioResult(msg)
+
+	case selectedSongsMsg:
+		return m.handleSelectedSongs(msg)
+
+	case upNextResultMsg:
+		return m.handleUpNextResult(msg)
+
+	case historyResultMsg:
+		return m.handleHistoryResult(msg)
+
+	case recentPlaysMsg:
+		return m.handleRecentPlays(msg)
+
+	case reportMsg:
+		return m.handleReport(msg)
+
+	case importResultMsg:
+		return m.handleImportResult(msg)
+
+	case syncPlanMsg:
+		return m.handleSyncPlan(msg)
+
+	case smartPlaylistMsg:
+		return m.handleSmartPlaylist(msg)
+
+	case continueQueueMsg:
+		return m.handleContinueQueue(msg)
+
 	case starredResultMsg:
 		return m.handleStarredResult(msg)
 
@@ -1,7 +1,6 @@
 package ui
 
 import (
-	"math/rand"
 	"strings"
 
 	"github.com/MattiaPun/SubTUI/v2/internal/api"
@@ -21,6 +20,22 @@ func (m model) handlesKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return login(m, msg)
 	}
 
+	if showMini(m) {
+		// The search bar is hidden, keys go to the player
+		if m.focus == focusSearch {
+			m.focus = focusMain
+			m.textInput.Blur()
+		}
+
+		if !isMediaKey(key) {
+			return miniMenu(key, m)
+		}
+	}
+
+	if m.showPlaying && !isMediaKey(key) {
+		return nowPlayingMenu(key, m)
+	}
+
 	if keyMatches(key, api.AppConfig.Keybinds.Global.CycleFocusNext) {
 		return cycleFocus(m, true), nil
 	}
@@ -30,6 +45,12 @@ func (m model) handlesKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	}
 
 	if m.focus == focusSearch {
+		// Backspace only cancels a prompt once it is empty
+		if m.prompt != promptNone && keyMatches(key, api.AppConfig.Keybinds.Global.Back) &&
+			(key != "backspace" || m.textInput.Value() == "") {
+			return cancelPrompt(m), nil
+		}
+
 		if keyMatches(key, api.AppConfig.Keybinds.Navigation.Select) {
 			return enter(m)
 		}
@@ -42,7 +63,25 @@ func (m model) handlesKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			return cycleFilter(m, false), nil
 		}
 
-		return typeInput(m, msg)
+		if m.prompt == promptNone && keyMatches(key, api.AppConfig.Keybinds.Search.HistoryPrev) {
+			return recallSearch(m, true), nil
+		}
+
+		if m.prompt == promptNone && keyMatches(key, api.AppConfig.Keybinds.Search.HistoryNext) {
+			return recallSearch(m, false), nil
+		}
+
+		if m.prompt == promptFilter {
+			m, cmd := typeInput(m, msg)
+			return applyLiveFilter(m, m.textInput.Value()), cmd
+		}
+
+		if m.prompt == promptFind {
+			m, cmd := typeInput(m, msg)
+			return applyFind(m, m.textInput.Value()), cmd
+		}
+
+		return typeSearch(m, msg)
 	}
 
 	if keyMatches(key, api.AppConfig.Keybinds.Global.Back) {
@@ -57,6 +96,29 @@ func (m model) handlesKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return ratingMenu(key, m)
 	}
 
+	if m.showStats {
+		return statsMenu(key, m)
+	}
+
+	if m.showReport {
+		return reportMenu(key, m)
+	}
+
+	if m.showProfiles {
+		return profilesMenu(key, m)
+	}
+
+	// Jump between finder matches, these take over the regular keys while the finder is active
+	if m.findQuery != "" && m.focus == m.findFocus {
+		if keyMatches(key, api.AppConfig.Keybinds.Search.FindNext) {
+			return findNext(m, true), nil
+		}
+
+		if keyMatches(key, api.AppConfig.Keybinds.Search.FindPrev) {
+			return findNext(m, false), nil
+		}
+	}
+
 	if keyMatches(key, api.AppConfig.Keybinds.Global.Help) {
 		m.showHelp = !m.showHelp
 		return m, nil
@@ -109,11 +171,43 @@ func (m model) handlesKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return playShuffeled(m)
 	}
 
+	if keyMatches(key, api.AppConfig.Keybinds.Navigation.ToggleSelect) {
+		return toggleSelect(m), nil
+	}
+
+	if keyMatches(key, api.AppConfig.Keybinds.Navigation.VisualMode) {
+		return toggleVisualMode(m), nil
+	}
+
+	if keyMatches(key, api.AppConfig.Keybinds.Navigation.SortBy) {
+		return cycleSort(m), nil
+	}
+
+	if keyMatches(key, api.AppConfig.Keybinds.Navigation.SortReverse) {
+		return reverseSort(m), nil
+	}
+
+	if keyMatches(key, api.AppConfig.Keybinds.Navigation.ViewBack) {
+		return navigateBack(m), nil
+	}
+
+	if keyMatches(key, api.AppConfig.Keybinds.Navigation.ViewForward) {
+		return navigateForward(m), nil
+	}
+
 	// SEARCH KEYBINDS
 	if keyMatches(key, api.AppConfig.Keybinds.Search.FocusSearch) {
 		return focusSearchBar(m), nil
 	}
 
+	if keyMatches(key, api.AppConfig.Keybinds.Search.Find) {
+		return openFind(m), nil
+	}
+
+	if keyMatches(key, api.AppConfig.Keybinds.Search.FilterList) && m.viewMode == viewList {
+		return openPrompt(m, promptFilter, m.liveFilter), nil
+	}
+
 	// LIBRARY KEYBINDS
 	if keyMatches(key, api.AppConfig.Keybinds.Library.AddToPlaylist) {
 		return toggleAddToPlaylistPopup(m), nil
@@ -123,6 +217,22 @@ func (m model) handlesKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return toggleAddRatingPopup(m), nil
 	}
 
+	if keyMatches(key, api.AppConfig.Keybinds.Library.StartRadio) {
+		return startRadio(m)
+	}
+
+	if keyMatches(key, api.AppConfig.Keybinds.Library.RefreshSmart) {
+		return refreshSmartPlaylists(m)
+	}
+
+	if keyMatches(key, api.AppConfig.Keybinds.Library.SyncPlaylists) {
+		return openPrompt(m, promptSync, api.AppConfig.App.PlaylistDir), nil
+	}
+
+	if keyMatches(key, api.AppConfig.Keybinds.Library.SaveSearch) {
+		return toggleSavedSearch(m)
+	}
+
 	// MEDIA KEYBINDS
 	if keyMatches(key, api.AppConfig.Keybinds.Media.PlayPause) {
 		return mediaTogglePlay(m, msg), nil
@@ -144,6 +254,10 @@ func (m model) handlesKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return mediaVolumeDown(m, msg)
 	}
 
+	if keyMatches(key, api.AppConfig.Keybinds.Media.Mute) {
+		return mediaToggleMute(m)
+	}
+
 	if keyMatches(key, api.AppConfig.Keybinds.Media.Shuffle) {
 		return mediaShuffle(m), nil
 	}
@@ -170,11 +284,11 @@ func (m model) handlesKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	}
 
 	if keyMatches(key, api.AppConfig.Keybinds.Queue.QueueNext) {
-		return mediaQueueNext(m), nil
+		return mediaQueueNext(m)
 	}
 
 	if keyMatches(key, api.AppConfig.Keybinds.Queue.QueueLast) {
-		return mediaQueueLast(m), nil
+		return mediaQueueLast(m)
 	}
 
 	if keyMatches(key, api.AppConfig.Keybinds.Queue.RemoveFromQueue) {
@@ -185,6 +299,22 @@ func (m model) handlesKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return mediaClearQueue(m), nil
 	}
 
+	if keyMatches(key, api.AppConfig.Keybinds.Queue.RemoveUpNext) {
+		return mediaRemoveUpNext(m)
+	}
+
+	if keyMatches(key, api.AppConfig.Keybinds.Queue.ClearUpNext) {
+		return mediaClearUpNext(m)
+	}
+
+	if keyMatches(key, api.AppConfig.Keybinds.Queue.Export) {
+		return openExportPrompt(m), nil
+	}
+
+	if keyMatches(key, api.AppConfig.Keybinds.Queue.Import) {
+		return openPrompt(m, promptImport, ""), nil
+	}
+
 	if keyMatches(key, api.AppConfig.Keybinds.Queue.MoveUp) {
 		return mediaSongUpQueue(m), nil
 	}
@@ -193,6 +323,14 @@ func (m model) handlesKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return mediaSongDownQueue(m), nil
 	}
 
+	if keyMatches(key, api.AppConfig.Keybinds.Queue.Undo) {
+		return mediaUndoQueue(m)
+	}
+
+	if keyMatches(key, api.AppConfig.Keybinds.Queue.Redo) {
+		return mediaRedoQueue(m)
+	}
+
 	// FAVORITES KEYBINDS
 	if keyMatches(key, api.AppConfig.Keybinds.Favorites.ToggleFavorite) {
 		return mediaToggleFavorite(m, msg)
@@ -202,6 +340,11 @@ func (m model) handlesKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return mediaShowFavorites(m, msg)
 	}
 
+	// NOW PLAYING KEYBINDS
+	if keyMatches(key, api.AppConfig.Keybinds.NowPlaying.Toggle) {
+		return toggleNowPlaying(m), nil
+	}
+
 	// OTHER KEYBINDS
 	if keyMatches(key, api.AppConfig.Keybinds.Other.CreateShareLink) {
 		return m, mediaCreateShare(m)
@@ -211,6 +354,35 @@ func (m model) handlesKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return toggleNotifications(m), nil
 	}
 
+	if keyMatches(key, api.AppConfig.Keybinds.Other.ToggleLevelMeter) {
+		return toggleLevelMeter(m)
+	}
+
+	if keyMatches(key, api.AppConfig.Keybinds.Other.ViewHistory) {
+		return showHistory(m)
+	}
+
+	if keyMatches(key, api.AppConfig.Keybinds.Other.ViewStats) {
+		m.loading = true
+		return m, loadHistoryCmd(true)
+	}
+
+	if keyMatches(key, api.AppConfig.Keybinds.Other.FilterProfiles) {
+		return openFilterProfiles(m), nil
+	}
+
+	if keyMatches(key, api.AppConfig.Keybinds.Other.ToggleHideFiltered) {
+		return toggleHideFiltered(m), nil
+	}
+
+	if keyMatches(key, api.AppConfig.Keybinds.Other.WhyFiltered) {
+		return whyFiltered(m)
+	}
+
+	if keyMatches(key, api.AppConfig.Keybinds.Other.ToggleMini) {
+		return toggleMini(m), nil
+	}
+
 	return m, nil
 }
 
@@ -235,7 +407,9 @@ func hardQuit(m model) (tea.Model, tea.Cmd) {
 
 func quit(m model, msg tea.Msg) (tea.Model, tea.Cmd) {
 	if m.focus != focusSearch {
-		return m, tea.Quit
+		// The song playing at exit ends in the history too
+		cmd := m.finishListening()
+		return m, tea.Sequence(cmd, tea.Quit)
 	} else {
 		return typeInput(m, msg)
 	}
@@ -243,6 +417,7 @@ func quit(m model, msg tea.Msg) (tea.Model, tea.Cmd) {
 
 func focusSearchBar(m model) model {
 	m.focus = focusSearch
+	m.historyPos = 0
 	m.textInput.SetValue("")
 	m.textInput.Focus()
 	return m
@@ -260,6 +435,7 @@ func cycleFocus(m model, forward bool) model {
 		m.textInput.Focus()
 	} else {
 		m.textInput.Blur()
+		m.closePrompt()
 	}
 
 	return m
@@ -268,28 +444,21 @@ func cycleFocus(m model, forward bool) model {
 func enter(m model) (tea.Model, tea.Cmd) {
 	switch m.focus {
 	case focusSearch:
+		if m.prompt != promptNone {
+			return submitPrompt(m)
+		}
+
 		query := m.textInput.Value()
 		if query != "" {
-			m.loading = true
+			// A pending search-as-you-type is not needed anymore
+			m.typingGen++
+			m.historyPos = 0
 			m.focus = focusMain
-			m.viewMode = viewList
 			m.textInput.Blur()
 
-			// Reset paging
-			m.pageOffset = 0
-			m.pageHasMore = true
-			m.lastSearchQuery = query
-
-			switch m.filterMode {
-			case filterSongs:
-				m.displayMode = displaySongs
-			case filterAlbums:
-				m.displayMode = displayAlbums
-			case filterArtist:
-				m.displayMode = displayArtist
-			}
-
-			return m, searchCmd(query, m.filterMode, 0)
+			recordSearch(query, m.filterMode)
+			m, cmd := startSearch(m, query)
+			return m, tea.Batch(cmd, saveStateCmd())
 		}
 
 	case focusMain:
@@ -305,25 +474,29 @@ func enter(m model) (tea.Model, tea.Cmd) {
 			case filterAlbums:
 				if len(m.albums) > 0 {
 					selectedAlbum := m.albums[m.cursorMain]
+					m.pushView(selectedAlbum.Name)
 					m.loading = true
-					m.displayModePrev = m.displayMode
 					m.displayMode = displaySongs
 					m.songs = nil
 
-					return m, getAlbumSongs(selectedAlbum.ID, false)
+					return m.requestAlbum(selectedAlbum.ID)
 				}
 
 				// Open albums of artist
 			case filterArtist:
 				if len(m.artists) > 0 {
 					selectedArtist := m.artists[m.cursorMain]
+					m.pushView(selectedArtist.Name)
 					m.loading = true
-					m.displayModePrev = m.displayMode
 					m.displayMode = displayAlbums
 					m.albums = nil
 
-					return m, getArtistAlbums(selectedArtist.ID)
+					return m.requestList(getArtistAlbums(selectedArtist.ID))
 				}
+
+			// Open or play the result, or show all results of a section
+			case displayAll:
+				return openAllRow(m)
 			}
 		} else {
 			// Queue View: Jump to selected song
@@ -335,6 +508,15 @@ func enter(m model) (tea.Model, tea.Cmd) {
 	case focusSidebar:
 		albumOffset := len(albumTypes)
 
+		if index := savedSearchIndex(m); index != -1 {
+			return openSavedSearch(m, index)
+		}
+
+		if index := smartPlaylistIndex(m); index != -1 {
+			return openSmartPlaylist(m, index, false)
+		}
+
+		m.pushView(sidebarName(m, m.cursorSide))
 		m.loading = true
 		m.focus = focusMain
 		m.viewMode = viewList
@@ -348,27 +530,27 @@ func enter(m model) (tea.Model, tea.Cmd) {
 			switch m.cursorSide {
 			case 0:
 				m.albumListType = "alphabeticalByArtist"
-				return m, getAlbumList("alphabeticalByArtist", 0)
+				return m.requestList(getAlbumList("alphabeticalByArtist", 0))
 			case 1:
 				m.albumListType = "random"
-				return m, getAlbumList("random", 0)
+				return m.requestList(getAlbumList("random", 0))
 			case 2:
 				m.albumListType = "starred"
-				return m, getAlbumList("starred", 0)
+				return m.requestList(getAlbumList("starred", 0))
 			case 3:
 				m.albumListType = "newest"
-				return m, getAlbumList("newest", 0)
+				return m.requestList(getAlbumList("newest", 0))
 			case 4:
 				m.albumListType = "recent"
-				return m, getAlbumList("recent", 0)
+				return m.requestList(getAlbumList("recent", 0))
 			case 5:
 				m.albumListType = "frequent"
-				return m, getAlbumList("frequent", 0)
+				return m.requestList(getAlbumList("frequent", 0))
 			}
 
 		} else {
 			m.displayMode = displaySongs
-			return m, getPlaylistSongs((m.playlists[m.cursorSide-albumOffset]).ID, false) // - because of the Album offset
+			return m.requestList(getPlaylistSongs((m.playlists[m.cursorSide-albumOffset]).ID, false)) // - because of the Album offset
 
 		}
 
@@ -377,6 +559,70 @@ func enter(m model) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// Helper: Search the library and show the results
+func startSearch(m model, query string) (model, tea.Cmd) {
+	return searchIn(m, query, m.filterMode)
+}
+
+func searchIn(m model, query string, mode int) (model, tea.Cmd) {
+	// Searching as you type keeps replacing the same view
+	title := searchTitle(query, mode)
+	if m.viewTyped {
+		m.viewTitle = title
+	} else {
+		m.pushView(title)
+	}
+
+	m.loading = true
+	m.viewMode = viewList
+
+	// Reset paging
+	m.pageOffset = 0
+	m.pageHasMore = true
+	m.lastSearchQuery = query
+	m.lastSearchMode = mode
+
+	switch mode {
+	case filterSongs:
+		m.displayMode = displaySongs
+	case filterAlbums:
+		m.displayMode = displayAlbums
+	case filterArtist:
+		m.displayMode = displayArtist
+	case filterAll:
+		m.displayMode = displayAll
+	}
+
+	return m.requestList(searchCmd(query, mode, 0))
+}
+
+// Helper: Send a request that replaces the shown list, making older ones stale
+func (m model) requestList(cmd tea.Cmd) (model, tea.Cmd) {
+	m.listGen++
+	return m, tagListCmd(cmd, m.listGen)
+}
+
+// Helper: Load the songs of an album, its cover is shown next to them
+func (m model) requestAlbum(albumID string) (model, tea.Cmd) {
+	m.detailCover = albumID
+	m, cmd := m.requestList(getAlbumSongs(albumID, false))
+
+	return m, tea.Batch(cmd, m.requestCover(albumID))
+}
+
+// Helper: Type in the search bar and search once typing pauses
+func typeSearch(m model, msg tea.Msg) (model, tea.Cmd) {
+	query := m.textInput.Value()
+	m, cmd := typeInput(m, msg)
+
+	if m.prompt != promptNone || api.AppConfig.App.SearchDebounce <= 0 || m.textInput.Value() == query {
+		return m, cmd
+	}
+
+	m.typingGen++
+	return m, tea.Batch(cmd, searchDebounceCmd(m.textInput.Value(), m.typingGen))
+}
+
 func playShuffeled(m model) (tea.Model, tea.Cmd) {
 	switch m.focus {
 	case focusMain:
@@ -387,6 +633,14 @@ func playShuffeled(m model) (tea.Model, tea.Cmd) {
 		}
 
 	case focusSidebar:
+		if index := smartPlaylistIndex(m); index != -1 {
+			return openSmartPlaylist(m, index, true)
+		}
+
+		if index := savedSearchIndex(m); index != -1 {
+			return openSavedSearch(m, index)
+		}
+
 		if m.cursorSide > (len(albumTypes)-1) && (m.playlists[m.cursorSide-len(albumTypes)]).ID != "" {
 			m.loading = true
 			m.displayMode = displaySongs
@@ -400,25 +654,38 @@ func playShuffeled(m model) (tea.Model, tea.Cmd) {
 }
 
 func goBack(m model) (tea.Model, tea.Cmd) {
-	if m.showHelp || m.showPlaylists || m.showRating {
+	if m.showHelp || m.showPlaylists || m.showRating || m.showStats || m.showReport || m.showProfiles {
 		m.showHelp = false
 		m.showPlaylists = false
 		m.showRating = false
+		m.showStats = false
+		m.showReport = false
+		m.showProfiles = false
+		m.syncPlans = nil
 
 		return m, nil
 	}
 
-	if m.viewMode == viewQueue {
-		return toggleQueue(m), nil
+	if hasSelection(m) {
+		m.clearSelection()
+		return m, nil
 	}
 
-	tempDisplay := m.displayMode
-	m.displayMode = m.displayModePrev
-	m.displayModePrev = tempDisplay
+	if m.findQuery != "" {
+		m.clearFind()
+		return m, nil
+	}
 
-	m.viewMode = viewList
+	if m.liveFilter != "" && m.viewMode == viewList {
+		m.clearLiveFilter()
+		return m, nil
+	}
 
-	return m, nil
+	if m.viewMode == viewQueue {
+		return toggleQueue(m), nil
+	}
+
+	return navigateBack(m), nil
 }
 
 func navigateTop(m model) model {
@@ -426,6 +693,7 @@ func navigateTop(m model) model {
 	case focusMain:
 		m.cursorMain = 0
 		m.mainOffset = 0
+		m = skipAllHeader(m)
 	case focusSidebar:
 		m.cursorSide = 0
 		m.sideOffset = 0
@@ -446,6 +714,8 @@ func navigateBottom(m model) (model, tea.Cmd) {
 			listLen = len(m.albums)
 		case displayArtist:
 			listLen = len(m.artists)
+		case displayAll:
+			listLen = len(allRows(m))
 		}
 
 		m.cursorMain = listLen - 1
@@ -456,7 +726,7 @@ func navigateBottom(m model) (model, tea.Cmd) {
 		}
 
 	case focusSidebar:
-		total := len(albumTypes) + len(m.playlists)
+		total := sidebarLen(m)
 		m.cursorSide = total - 1
 
 		headerHeight := 1
@@ -492,6 +762,14 @@ func navigateUp(m model) model {
 		if m.cursorMain < m.mainOffset {
 			m.mainOffset = m.cursorMain
 		}
+
+		// Step over section headers of the search overview and hidden queue songs
+		if isSkippedRow(m, m.cursorMain) {
+			if m.cursorMain == 0 {
+				return skipHiddenRow(m)
+			}
+			return navigateUp(m)
+		}
 	} else if m.focus == focusSidebar && m.cursorSide > 0 {
 		m.cursorSide--
 		if m.cursorSide < m.sideOffset {
@@ -512,18 +790,27 @@ func navigateDown(m model) (model, tea.Cmd) {
 		listLen = len(m.albums)
 	} else if m.displayMode == displayArtist {
 		listLen = len(m.artists)
+	} else if m.displayMode == displayAll {
+		listLen = len(allRows(m))
 	}
 
-	albumOffset := len(albumTypes)
 	if m.focus == focusMain && m.cursorMain < listLen-1 {
 		m.cursorMain++
 
 		// Height - Search(3) - Footer(6) - Margins(4) - TableHeader(2) = 17
-		visibleRows := m.height - 17
+		visibleRows := m.height - 17 - upNextHeight(m)
 		if m.cursorMain >= m.mainOffset+visibleRows {
 			m.mainOffset++
 		}
-	} else if m.focus == focusSidebar && m.cursorSide < len(m.playlists)+albumOffset-1 { // + because of the Album offset
+
+		// Step over section headers of the search overview and hidden queue songs
+		if isSkippedRow(m, m.cursorMain) {
+			if m.cursorMain == listLen-1 {
+				return skipHiddenRow(m), nil
+			}
+			return navigateDown(m)
+		}
+	} else if m.focus == focusSidebar && m.cursorSide < sidebarLen(m)-1 {
 		m.cursorSide++
 
 		headerHeight := 1
@@ -558,29 +845,29 @@ func displayAlbumFromSelected(m model) (tea.Model, tea.Cmd) {
 		return m, nil
 	}
 
-	albumID := ""
+	albumID, albumName := "", ""
 	if m.viewMode == viewList && len(m.songs) != 0 {
 		// album of a songs
-		albumID = m.songs[m.cursorMain].AlbumID
+		albumID, albumName = m.songs[m.cursorMain].AlbumID, m.songs[m.cursorMain].Album
 	} else if m.viewMode == viewQueue && len(m.queue) != 0 {
 		// album of a queued song
-		albumID = m.queue[m.cursorMain].AlbumID
+		albumID, albumName = m.queue[m.cursorMain].AlbumID, m.queue[m.cursorMain].Album
 	}
 
 	if albumID == "" {
 		return m, nil
 	}
 
+	m.pushView(albumName)
 	m.loading = true
 	m.mainOffset = 0
 	m.cursorMain = 0
 	m.lastSearchQuery = ""
 
 	m.viewMode = viewList
-	m.displayModePrev = m.displayMode
 	m.displayMode = displaySongs
 
-	return m, getAlbumSongs(albumID, false)
+	return m.requestAlbum(albumID)
 }
 
 func displayArtistFromSelected(m model) (tea.Model, tea.Cmd) {
@@ -589,43 +876,44 @@ func displayArtistFromSelected(m model) (tea.Model, tea.Cmd) {
 		return m, nil
 	}
 
-	artistID := ""
+	artistID, artistName := "", ""
 	if m.viewMode == viewList {
 		if m.displayMode == displaySongs && len(m.songs) != 0 {
 			// artist of a songs
-			artistID = m.songs[m.cursorMain].ArtistID
+			artistID, artistName = m.songs[m.cursorMain].ArtistID, m.songs[m.cursorMain].Artist
 		} else if m.displayMode == displayAlbums && len(m.albums) != 0 {
 			// artist of an album
-			artistID = m.albums[m.cursorMain].ArtistID
+			artistID, artistName = m.albums[m.cursorMain].ArtistID, m.albums[m.cursorMain].Artist
 		}
 	} else if m.viewMode == viewQueue && len(m.queue) != 0 {
 		// artist of a queued song
-		artistID = m.queue[m.cursorMain].ArtistID
+		artistID, artistName = m.queue[m.cursorMain].ArtistID, m.queue[m.cursorMain].Artist
 	}
 
 	if artistID == "" {
 		return m, nil
 	}
 
+	m.pushView(artistName)
 	m.loading = true
 	m.mainOffset = 0
 	m.cursorMain = 0
 	m.lastSearchQuery = ""
 
 	m.viewMode = viewList
-	m.displayModePrev = m.displayMode
 	m.displayMode = displayAlbums
 
-	return m, getArtistAlbums(artistID)
+	return m.requestList(getArtistAlbums(artistID))
 }
 
 func cycleFilter(m model, forward bool) model {
 	if m.focus == focusSearch {
 		if forward {
-			m.filterMode = (m.filterMode + 1) % 3
+			m.filterMode = (m.filterMode + 1) % 4
 		} else {
-			m.filterMode = ((m.filterMode-1)%3 + 3) % 3
+			m.filterMode = ((m.filterMode-1)%4 + 4) % 4
 		}
+		m.historyPos = 0
 
 		switch m.filterMode {
 		case filterSongs:
@@ -634,6 +922,8 @@ func cycleFilter(m model, forward bool) model {
 			m.textInput.Placeholder = "Search albums..."
 		case filterArtist:
 			m.textInput.Placeholder = "Search artists..."
+		case filterAll:
+			m.textInput.Placeholder = "Search everything..."
 		}
 	}
 
@@ -642,6 +932,7 @@ func cycleFilter(m model, forward bool) model {
 
 func toggleQueue(m model) model {
 	if m.focus != focusSearch {
+		m.clearSelection()
 
 		switch m.viewMode {
 		case viewList:
@@ -709,69 +1000,133 @@ func mediaSongPrev(m model, msg tea.Msg) (tea.Model, tea.Cmd) {
 func mediaVolumeUp(m model, _ tea.Msg) (tea.Model, tea.Cmd) {
 	player.VolumeUp()
 	m.playerStatus.Volume = player.GetVolume()
-	return m, nil
+	api.AppState.Player.Volume = int(m.playerStatus.Volume)
+	return m, saveStateCmd()
 }
 
 func mediaVolumeDown(m model, _ tea.Msg) (tea.Model, tea.Cmd) {
 	player.VolumeDown()
 	m.playerStatus.Volume = player.GetVolume()
-	return m, nil
+	api.AppState.Player.Volume = int(m.playerStatus.Volume)
+	return m, saveStateCmd()
 }
 
-func mediaQueueNext(m model) model {
-	if m.focus == focusMain && (m.displayMode == displaySongs || m.displayMode == displayAlbums) {
-		selectedSongs := getSelectedSongs(m)
+func mediaToggleMute(m model) (tea.Model, tea.Cmd) {
+	if m.focus == focusSearch {
+		return m, nil
+	}
 
-		if selectedSongs != nil {
-			if len(m.queue) == 0 {
-				m.queue = selectedSongs
-				m.queueIndex = 0
-			} else {
-				insertAt := m.queueIndex + 1
-				tail := append([]api.Song{}, m.queue[insertAt:]...)
-				m.queue = append(m.queue[:insertAt], append(selectedSongs, tail...)...)
-			}
+	player.ToggleMute()
+	m.playerStatus.Muted = player.IsMuted()
+	api.AppState.Player.Muted = m.playerStatus.Muted
+	return m, saveStateCmd()
+}
 
-			if m.viewMode == viewQueue && m.cursorMain > m.queueIndex {
-				m.cursorMain++
-			}
+func mediaQueueNext(m model) (model, tea.Cmd) {
+	if m.focus != focusMain || m.displayMode == displayArtist {
+		return m, nil
+	}
+
+	return withSelectedSongs(m, selectionQueueNext, "")
+}
+
+// Takes the songs under the cursor or in the selection out of up next, the first entry of each
+func mediaRemoveUpNext(m model) (model, tea.Cmd) {
+	if m.focus != focusMain || len(m.upNext) == 0 {
+		return m, nil
+	}
+
+	remove := make(map[string]bool)
+	for _, song := range getSelectedSongs(m) {
+		remove[song.ID] = true
+	}
+
+	upNext := []api.Song{}
+	for _, song := range m.upNext {
+		if remove[song.ID] {
+			delete(remove, song.ID)
+			continue
 		}
+
+		upNext = append(upNext, song)
+	}
+
+	if len(upNext) == len(m.upNext) {
+		return m, nil
 	}
 
+	m.recordQueue()
+	m.upNext = upNext
+	m.syncUpNextState()
+	m.clearSelection()
+
 	// Sync MPV's Queue
 	m.syncNextSong()
 
-	return m
+	return m, saveStateCmd()
 }
 
-func mediaQueueLast(m model) model {
-	if m.focus == focusMain && (m.displayMode == displaySongs || m.displayMode == displayAlbums) {
-		selectedSongs := getSelectedSongs(m)
-
-		if selectedSongs != nil {
-			m.queue = append(m.queue, selectedSongs...)
-		}
-
+func mediaClearUpNext(m model) (model, tea.Cmd) {
+	if m.focus == focusSearch || len(m.upNext) == 0 {
+		return m, nil
 	}
 
+	m.recordQueue()
+	m.upNext = nil
+	m.syncUpNextState()
+
 	// Sync MPV's Queue
 	m.syncNextSong()
 
-	return m
+	return m, saveStateCmd()
+}
+
+func mediaQueueLast(m model) (model, tea.Cmd) {
+	if m.focus != focusMain || m.displayMode == displayArtist {
+		return m, nil
+	}
+
+	return withSelectedSongs(m, selectionQueueLast, "")
 }
 
 func mediaDeleteSongFromQueue(m model) model {
 	if m.focus == focusMain && m.viewMode == viewQueue && len(m.queue) > 0 {
-		if m.cursorMain != m.queueIndex {
-			m.queue = append(m.queue[:m.cursorMain], m.queue[m.cursorMain+1:]...)
-			if m.cursorMain < m.queueIndex {
-				m.queueIndex--
+		remove := make(map[int]bool)
+		for _, i := range selectedIndices(m) {
+			// The playing song can't be removed
+			if i != m.queueIndex {
+				remove[i] = true
 			}
 		}
+
+		if len(remove) > 0 {
+			m.recordQueue()
+		}
+
+		newQueue := []api.Song{}
+		removedSongs := []api.Song{}
+		removedBefore := 0
+		for i, song := range m.queue {
+			if remove[i] {
+				if i < m.queueIndex {
+					removedBefore++
+				}
+				removedSongs = append(removedSongs, song)
+				continue
+			}
+
+			newQueue = append(newQueue, song)
+		}
+
+		m.removeFromOriginal(removedSongs)
+
+		m.queue = newQueue
+		m.queueIndex -= removedBefore
+		m.clearSelection()
 	}
 
 	if m.cursorMain >= len(m.queue) && m.cursorMain > 0 {
-		m.cursorMain--
+		m.cursorMain = len(m.queue) - 1
 	}
 
 	// Sync MPV's Queue
@@ -781,9 +1136,11 @@ func mediaDeleteSongFromQueue(m model) model {
 }
 
 func mediaClearQueue(m model) model {
-	if m.focus == focusMain {
+	if m.focus == focusMain && len(m.queue) > 0 {
+		m.recordQueue()
 		m.queue = nil
 		m.queueIndex = 0
+		m.queueOriginal = nil
 	}
 
 	// Sync MPV's Queue
@@ -793,20 +1150,39 @@ func mediaClearQueue(m model) model {
 }
 
 func mediaSongUpQueue(m model) model {
-	if m.focus == focusMain && m.viewMode == viewQueue && m.cursorMain > 0 {
-		tempSong := m.queue[m.cursorMain]
+	if m.focus == focusMain && m.viewMode == viewQueue {
+		m.commitVisual()
+		indices := selectedIndices(m)
+
+		if len(indices) > 0 && indices[0] > 0 {
+			m.recordQueue()
+			m.queue = append([]api.Song{}, m.queue...)
+
+			// Move every selected song up one row, the song above the block moves below it
+			for _, i := range indices {
+				m.queue[i], m.queue[i-1] = m.queue[i-1], m.queue[i]
+
+				switch m.queueIndex {
+				case i:
+					m.queueIndex--
+				case i - 1:
+					m.queueIndex++
+				}
+			}
 
-		m.queue[m.cursorMain] = m.queue[m.cursorMain-1]
-		m.queue[m.cursorMain-1] = tempSong
+			if len(m.selection) > 0 {
+				newSelection := make(map[int]bool)
+				for _, i := range indices {
+					newSelection[i-1] = true
+				}
+				m.selection = newSelection
+			}
 
-		switch m.queueIndex {
-		case m.cursorMain:
-			m.queueIndex--
-		case m.cursorMain - 1:
-			m.queueIndex++
+			m.cursorMain--
+			if m.cursorMain < m.mainOffset {
+				m.mainOffset = m.cursorMain
+			}
 		}
-
-		m.cursorMain--
 	}
 
 	// Sync MPV's Queue
@@ -816,20 +1192,37 @@ func mediaSongUpQueue(m model) model {
 }
 
 func mediaSongDownQueue(m model) model {
-	if m.focus == focusMain && m.viewMode == viewQueue && m.cursorMain < len(m.queue)-1 {
-		tempSong := m.queue[m.cursorMain]
+	if m.focus == focusMain && m.viewMode == viewQueue {
+		m.commitVisual()
+		indices := selectedIndices(m)
+
+		if len(indices) > 0 && indices[len(indices)-1] < len(m.queue)-1 {
+			m.recordQueue()
+			m.queue = append([]api.Song{}, m.queue...)
+
+			// Move every selected song down one row, the song below the block moves above it
+			for j := len(indices) - 1; j >= 0; j-- {
+				i := indices[j]
+				m.queue[i], m.queue[i+1] = m.queue[i+1], m.queue[i]
+
+				switch m.queueIndex {
+				case i:
+					m.queueIndex++
+				case i + 1:
+					m.queueIndex--
+				}
+			}
 
-		m.queue[m.cursorMain] = m.queue[m.cursorMain+1]
-		m.queue[m.cursorMain+1] = tempSong
+			if len(m.selection) > 0 {
+				newSelection := make(map[int]bool)
+				for _, i := range indices {
+					newSelection[i+1] = true
+				}
+				m.selection = newSelection
+			}
 
-		switch m.queueIndex {
-		case m.cursorMain:
-			m.queueIndex++
-		case m.cursorMain + 1:
-			m.queueIndex--
+			m.cursorMain++
 		}
-
-		m.cursorMain++
 	}
 
 	// Sync MPV's Queue
@@ -838,6 +1231,32 @@ func mediaSongDownQueue(m model) model {
 	return m
 }
 
+func mediaUndoQueue(m model) (model, tea.Cmd) {
+	if m.focus == focusSearch || len(m.queueHistory) == 0 {
+		return m, nil
+	}
+
+	snapshot := m.queueHistory[len(m.queueHistory)-1]
+	m.queueHistory = m.queueHistory[:len(m.queueHistory)-1]
+	m.queueFuture = append(m.queueFuture, m.queueSnapshot())
+
+	cmd := m.restoreQueue(snapshot)
+	return m, tea.Batch(cmd, saveStateCmd())
+}
+
+func mediaRedoQueue(m model) (model, tea.Cmd) {
+	if m.focus == focusSearch || len(m.queueFuture) == 0 {
+		return m, nil
+	}
+
+	snapshot := m.queueFuture[len(m.queueFuture)-1]
+	m.queueFuture = m.queueFuture[:len(m.queueFuture)-1]
+	m.queueHistory = append(m.queueHistory, m.queueSnapshot())
+
+	cmd := m.restoreQueue(snapshot)
+	return m, tea.Batch(cmd, saveStateCmd())
+}
+
 func mediaRestartSong(m model) model {
 	if m.focus != focusSearch {
 		player.RestartSong()
@@ -863,38 +1282,45 @@ func mediaSeekRewind(m model) model {
 }
 
 func mediaShuffle(m model) model {
-	if m.focus != focusSearch {
-		if len(m.queue) < 2 {
-			return m
-		}
+	if m.focus == focusSearch {
+		return m
+	}
 
-		newQueue := make([]api.Song, len(m.queue))
-		copy(newQueue, m.queue)
-		m.queue = newQueue
+	m.recordQueue()
 
-		currentSongID := ""
-		if m.queueIndex >= 0 && m.queueIndex < len(m.queue) {
-			currentSongID = m.queue[m.queueIndex].ID
-		}
+	if m.shuffled {
+		// Restore the original order, keeping the current song playing
+		m.shuffled = false
 
-		rand.Shuffle(len(m.queue), func(i, j int) {
-			m.queue[i], m.queue[j] = m.queue[j], m.queue[i]
-		})
+		if len(m.queueOriginal) > 0 {
+			currentEntry := 0
+			if m.queueIndex >= 0 && m.queueIndex < len(m.queue) {
+				currentEntry = m.queue[m.queueIndex].QueueEntry
+			}
 
-		if currentSongID != "" {
-			for i, song := range m.queue {
-				if song.ID == currentSongID {
-					// Swap the song at i with 0 to set current song to first
-					m.queue[0], m.queue[i] = m.queue[i], m.queue[0]
-					m.queueIndex = 0
-					break
-				}
+			m.queue = m.queueOriginal
+			m.queueIndex = 0
+			if index := findQueueEntry(m.queue, currentEntry); index != -1 {
+				m.queueIndex = index
 			}
-		} else {
+		}
+
+		m.queueOriginal = nil
+	} else {
+		m.shuffled = true
+
+		if len(m.queue) > 1 {
+			m.tagQueueEntries(m.queue)
+			m.queueOriginal = append([]api.Song{}, m.queue...)
+			m.queue = m.shuffleWithFirst(m.queue, m.queueIndex)
 			m.queueIndex = 0
 		}
 	}
 
+	if m.viewMode == viewQueue {
+		m.clearSelection()
+	}
+
 	// Sync MPV's Queue
 	m.syncNextSong()
 
@@ -917,45 +1343,59 @@ func mediaToggleFavorite(m model, msg tea.Msg) (model, tea.Cmd) {
 		return typeInput(m, msg)
 	}
 
-	id := ""
-
-	switch m.displayMode {
-	case displaySongs:
-
-		var targetList []api.Song
-		switch m.viewMode {
-		case viewList:
-			targetList = m.songs
-		case viewQueue:
-			targetList = m.queue
+	ids := []string{}
+	for _, i := range selectedIndices(m) {
+		switch m.displayMode {
+		case displaySongs:
+			switch m.viewMode {
+			case viewList:
+				ids = append(ids, m.songs[i].ID)
+			case viewQueue:
+				ids = append(ids, m.queue[i].ID)
+			}
+		case displayAlbums:
+			ids = append(ids, m.albums[i].ID)
+		case displayArtist:
+			ids = append(ids, m.artists[i].ID)
 		}
+	}
 
-		if len(targetList) > 0 {
-			id = targetList[m.cursorMain].ID
-		}
-	case displayAlbums:
-		if len(m.albums) > 0 {
-			id = m.albums[m.cursorMain].ID
-		}
-	case displayArtist:
-		if len(m.artists) > 0 {
-			id = m.artists[m.cursorMain].ID
-		}
+	for _, row := range selectedAllRows(m) {
+		id, _ := allRowItem(m, row)
+		ids = append(ids, id)
 	}
 
-	if id == "" {
+	if len(ids) == 0 {
 		return m, nil
 	}
 
-	isStarred := m.starredMap[id]
+	// Unstar only when every selected item is starred
+	allStarred := true
+	for _, id := range ids {
+		if !m.starredMap[id] {
+			allStarred = false
+			break
+		}
+	}
+
+	var cmds []tea.Cmd
+	for _, id := range ids {
+		if id == "" || m.starredMap[id] != allStarred {
+			continue
+		}
 
-	if isStarred {
-		delete(m.starredMap, id)
-	} else {
-		m.starredMap[id] = true
+		if allStarred {
+			delete(m.starredMap, id)
+		} else {
+			m.starredMap[id] = true
+		}
+
+		cmds = append(cmds, toggleStarCmd(id, allStarred))
 	}
 
-	return m, toggleStarCmd(id, isStarred)
+	m.clearSelection()
+
+	return m, tea.Batch(cmds...)
 }
 
 func mediaShowFavorites(m model, msg tea.Msg) (model, tea.Cmd) {
@@ -963,6 +1403,7 @@ func mediaShowFavorites(m model, msg tea.Msg) (model, tea.Cmd) {
 		return typeInput(m, msg)
 	}
 
+	m.pushView("Starred")
 	m.displayMode = displaySongs
 
 	m.songs = nil
@@ -972,9 +1413,25 @@ func mediaShowFavorites(m model, msg tea.Msg) (model, tea.Cmd) {
 	return m, openLikedSongsCmd()
 }
 
+func showHistory(m model) (model, tea.Cmd) {
+	m.pushView("History")
+	m.displayMode = displaySongs
+
+	m.songs = nil
+	m.viewMode = viewList
+	m.focus = focusMain
+	m.loading = true
+
+	return m, loadHistoryCmd(false)
+}
+
 func toggleAddToPlaylistPopup(m model) model {
-	if m.focus == focusMain && m.displayMode == displaySongs &&
-		((m.viewMode == viewList && len(m.songs) > 0) || (m.viewMode == viewQueue && len(m.queue) > 0)) {
+	// Artists of the search overview can't be added
+	if m.displayMode == displayAll && len(getSelectedSongs(m)) == 0 && len(getSelectedAlbumIDs(m)) == 0 {
+		return m
+	}
+
+	if m.focus == focusMain && m.displayMode != displayArtist && cursorInBounds(m) {
 		m.showPlaylists = !m.showPlaylists
 
 		if m.showPlaylists {
@@ -1010,11 +1467,64 @@ func toggleAddRatingPopup(m model) model {
 			m.cursorPopup = m.artists[m.cursorMain].Rating
 			m.showRating = !m.showRating
 		}
+	case displayAll:
+		if rows := selectedAllRows(m); len(rows) > 0 {
+			_, m.cursorPopup = allRowItem(m, rows[0])
+			m.showRating = !m.showRating
+		}
 	}
 
 	return m
 }
 
+func startRadio(m model) (model, tea.Cmd) {
+	var seed radioSeed
+
+	switch m.focus {
+	case focusMain:
+		if !cursorInBounds(m) {
+			return m, nil
+		}
+
+		switch {
+		case m.viewMode == viewQueue:
+			song := m.queue[m.cursorMain]
+			seed = radioSeed{song: &song, similarID: song.ID, artistName: song.Artist}
+
+		case m.displayMode == displaySongs:
+			song := m.songs[m.cursorMain]
+			seed = radioSeed{song: &song, similarID: song.ID, artistName: song.Artist}
+
+		case m.displayMode == displayAlbums:
+			album := m.albums[m.cursorMain]
+			seed = radioSeed{similarID: album.ID, artistName: album.Artist}
+
+		case m.displayMode == displayArtist:
+			artist := m.artists[m.cursorMain]
+			seed = radioSeed{artistID: artist.ID, artistName: artist.Name}
+		}
+
+	case focusSidebar:
+		playlistIndex := m.cursorSide - len(albumTypes)
+		if playlistIndex < 0 || playlistIndex >= len(m.playlists) {
+			return m, nil
+		}
+
+		seed = radioSeed{playlistID: m.playlists[playlistIndex].ID}
+
+	default:
+		return m, nil
+	}
+
+	size := api.AppConfig.App.RadioSize
+	if size <= 0 {
+		size = 50
+	}
+
+	m.loading = true
+	return m, startRadioCmd(seed, size)
+}
+
 func mediaCreateShare(m model) tea.Cmd {
 	if m.focus != focusMain {
 		return nil
@@ -1048,6 +1558,23 @@ func toggleNotifications(m model) model {
 	return m
 }
 
+func toggleLevelMeter(m model) (model, tea.Cmd) {
+	if m.focus == focusSearch {
+		return m, nil
+	}
+
+	m.levelMeter = !m.levelMeter
+	m.levelMeterTick++
+	m.audioLevels = player.AudioLevels{Left: player.SilenceLevel, Right: player.SilenceLevel}
+	go player.SetLevelMeter(m.levelMeter)
+
+	if !m.levelMeter {
+		return m, nil
+	}
+
+	return m, syncLevelsCmd(m.levelMeterTick)
+}
+
 func (m *model) updateLoginInputs(msg tea.Msg) tea.Cmd {
 	cmds := make([]tea.Cmd, len(m.loginInputs))
 	for i := range m.loginInputs {
@@ -1138,13 +1665,7 @@ func playlistsMenu(key string, m model) (model, tea.Cmd) {
 			m.cursorPopup++
 		}
 	} else if keyMatches(key, api.AppConfig.Keybinds.Navigation.Select) {
-		inBounds := cursorInBounds(m)
-
-		if m.viewMode == viewList && inBounds {
-			cmd = addSongToPlaylistCmd(m.songs[m.cursorMain].ID, m.playlists[m.cursorPopup].ID)
-		} else if m.viewMode == viewQueue && inBounds {
-			cmd = addSongToPlaylistCmd(m.queue[m.cursorMain].ID, m.playlists[m.cursorPopup].ID)
-		}
+		m, cmd = withSelectedSongs(m, selectionPlaylist, m.playlists[m.cursorPopup].ID)
 		m.showPlaylists = !m.showPlaylists
 		return m, cmd
 	}
@@ -1152,8 +1673,68 @@ func playlistsMenu(key string, m model) (model, tea.Cmd) {
 	return m, nil
 }
 
+func reportMenu(key string, m model) (model, tea.Cmd) {
+	if !keyMatches(key, api.AppConfig.Keybinds.Navigation.Select) {
+		return m, nil
+	}
+
+	m.showReport = false
+
+	// Apply a pending playlist sync
+	if len(m.syncPlans) > 0 {
+		plans := m.syncPlans
+		m.syncPlans = nil
+		m.loading = true
+
+		return m, tea.Sequence(applySyncCmd(plans), getPlaylists())
+	}
+
+	return m, nil
+}
+
+func statsMenu(key string, m model) (model, tea.Cmd) {
+	if keyMatches(key, api.AppConfig.Keybinds.Other.ViewStats) {
+		m.showStats = false
+		return m, nil
+	}
+
+	if keyMatches(key, api.AppConfig.Keybinds.Navigation.Up) {
+		m.statsPeriod = (m.statsPeriod - 1 + len(statsPeriods)) % len(statsPeriods)
+	} else if keyMatches(key, api.AppConfig.Keybinds.Navigation.Down) {
+		m.statsPeriod = (m.statsPeriod + 1) % len(statsPeriods)
+	} else {
+		return m, nil
+	}
+
+	m.stats = computeStats(m.history, statsPeriods[m.statsPeriod])
+	return m, nil
+}
+
+func profilesMenu(key string, m model) (model, tea.Cmd) {
+	if keyMatches(key, api.AppConfig.Keybinds.Other.FilterProfiles) {
+		m.showProfiles = false
+		return m, nil
+	}
+
+	names := filterProfileNames()
+
+	if keyMatches(key, api.AppConfig.Keybinds.Navigation.Up) && m.cursorPopup > 0 {
+		m.cursorPopup--
+	} else if keyMatches(key, api.AppConfig.Keybinds.Navigation.Down) && m.cursorPopup < len(names)-1 {
+		m.cursorPopup++
+	} else if keyMatches(key, api.AppConfig.Keybinds.Navigation.Select) {
+		m.showProfiles = false
+		m.setFilterProfile(names[m.cursorPopup])
+		m.cursorPopup = 0
+
+		api.AppState.Filters.Profile = m.filterProfile
+		return m, saveStateCmd()
+	}
+
+	return m, nil
+}
+
 func ratingMenu(key string, m model) (model, tea.Cmd) {
-	var cmd tea.Cmd
 	if keyMatches(key, api.AppConfig.Keybinds.Global.Back) || keyMatches(key, api.AppConfig.Keybinds.Library.AddRating) {
 		m.showRating = false
 		m.cursorPopup = 0
@@ -1165,28 +1746,41 @@ func ratingMenu(key string, m model) (model, tea.Cmd) {
 	} else if keyMatches(key, api.AppConfig.Keybinds.Navigation.Down) && m.cursorPopup < 5 {
 		m.cursorPopup++
 	} else if keyMatches(key, api.AppConfig.Keybinds.Navigation.Select) && cursorInBounds(m) {
+		var cmds []tea.Cmd
 
-		switch m.displayMode {
-		case displaySongs:
-			switch m.viewMode {
-			case viewList:
-				m.songs[m.cursorMain].Rating = m.cursorPopup
-				cmd = addRatingCmd(m.songs[m.cursorMain].ID, m.cursorPopup)
-			case viewQueue:
-				m.songs[m.cursorMain].Rating = m.cursorPopup
-				cmd = addRatingCmd(m.queue[m.cursorMain].ID, m.cursorPopup)
+		for _, i := range selectedIndices(m) {
+			switch m.displayMode {
+			case displaySongs:
+				switch m.viewMode {
+				case viewList:
+					m.songs[i].Rating = m.cursorPopup
+					m.syncRating(m.songs[i].ID, m.cursorPopup)
+					cmds = append(cmds, addRatingCmd(m.songs[i].ID, m.cursorPopup))
+				case viewQueue:
+					m.queue[i].Rating = m.cursorPopup
+					cmds = append(cmds, addRatingCmd(m.queue[i].ID, m.cursorPopup))
+				}
+			case displayAlbums:
+				cmds = append(cmds, addRatingCmd(m.albums[i].ID, m.cursorPopup))
+				m.albums[i].Rating = m.cursorPopup
+				m.syncRating(m.albums[i].ID, m.cursorPopup)
+			case displayArtist:
+				cmds = append(cmds, addRatingCmd(m.artists[i].ID, m.cursorPopup))
+				m.artists[i].Rating = m.cursorPopup
+				m.syncRating(m.artists[i].ID, m.cursorPopup)
 			}
-		case displayAlbums:
-			cmd = addRatingCmd(m.albums[m.cursorMain].ID, m.cursorPopup)
-			m.albums[m.cursorMain].Rating = m.cursorPopup
-		case displayArtist:
-			cmd = addRatingCmd(m.artists[m.cursorMain].ID, m.cursorPopup)
-			m.artists[m.cursorMain].Rating = m.cursorPopup
 		}
 
+		for _, row := range selectedAllRows(m) {
+			id, _ := allRowItem(m, row)
+			m.rateAllRow(row, m.cursorPopup)
+			cmds = append(cmds, addRatingCmd(id, m.cursorPopup))
+		}
+
+		m.clearSelection()
 		m.cursorPopup = 0
 		m.showRating = !m.showRating
-		return m, cmd
+		return m, tea.Batch(cmds...)
 	}
 
 	return m, nil
@@ -1194,12 +1788,12 @@ func ratingMenu(key string, m model) (model, tea.Cmd) {
 
 // Helper for infinte scrolling
 func loadMore(m model) (model, tea.Cmd) {
-	if m.focus == focusMain && m.pageHasMore && !m.loading {
+	if m.focus == focusMain && m.pageHasMore && !m.loading && m.liveFilter == "" && !m.findNarrows() {
 		// Songs
 		if m.displayMode == displaySongs && len(m.songs)-m.cursorMain <= 10 && m.lastSearchQuery != "" {
 			m.loading = true
 			m.pageOffset += 150
-			return m, searchCmd(m.lastSearchQuery, filterSongs, m.pageOffset)
+			return m.requestList(searchCmd(m.lastSearchQuery, filterSongs, m.pageOffset))
 		}
 
 		// Albums
@@ -1209,9 +1803,9 @@ func loadMore(m model) (model, tea.Cmd) {
 
 			// Check if search or sidebar loading
 			if m.lastSearchQuery != "" {
-				return m, searchCmd(m.lastSearchQuery, filterAlbums, m.pageOffset)
+				return m.requestList(searchCmd(m.lastSearchQuery, filterAlbums, m.pageOffset))
 			} else {
-				return m, getAlbumList(m.albumListType, m.pageOffset)
+				return m.requestList(getAlbumList(m.albumListType, m.pageOffset))
 			}
 		}
 
@@ -1219,7 +1813,7 @@ func loadMore(m model) (model, tea.Cmd) {
 		if m.displayMode == displayArtist && len(m.artists)-m.cursorMain <= 10 && m.lastSearchQuery != "" {
 			m.loading = true
 			m.pageOffset += 150
-			return m, searchCmd(m.lastSearchQuery, filterArtist, m.pageOffset)
+			return m.requestList(searchCmd(m.lastSearchQuery, filterArtist, m.pageOffset))
 		}
 	}
 
@@ -1242,6 +1836,9 @@ func cursorInBounds(m model) bool {
 
 	case displayArtist:
 		return m.cursorMain >= 0 && m.cursorMain < len(m.artists)
+
+	case displayAll:
+		return m.cursorMain >= 0 && m.cursorMain < len(allRows(m))
 	}
 
 	return false
@@ -4,7 +4,6 @@ import (
 	"fmt"
 	"log"
 	"math"
-	"math/rand"
 	"strings"
 	"time"
 
@@ -18,16 +17,18 @@ import (
 )
 
 const doubleClickThreshold = 500 * time.Millisecond
+const reportLimit = 10
 
 func (m model) handleWindowResize(msg tea.WindowSizeMsg) (tea.Model, tea.Cmd) {
 	m.width = msg.Width
 	m.height = msg.Height
+	m.coverFrame.reset()
 
 	return m, nil
 }
 
 func (m model) handleMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
-	if msg.Action != tea.MouseActionRelease || msg.Button != tea.MouseButtonLeft {
+	if msg.Action != tea.MouseActionRelease || msg.Button != tea.MouseButtonLeft || m.showPlaying || showMini(m) {
 		return m, nil
 	}
 
@@ -56,6 +57,7 @@ func (m model) handleMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
 	} else if msg.Y > listStartY+mainHeight { // Footer
 		m.focus = focusSong
 		m.textInput.Blur()
+		m.closePrompt()
 
 		return m, nil
 	}
@@ -63,8 +65,9 @@ func (m model) handleMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
 	if msg.X < sidebarWidth { // Sidebar
 		m.focus = focusSidebar
 		m.textInput.Blur()
+		m.closePrompt()
 
-		totalItems := len(albumTypes) + len(m.playlists)
+		totalItems := sidebarLen(m)
 		endIndex := m.sideOffset + mainHeight
 		if endIndex > totalItems {
 			endIndex = totalItems
@@ -88,6 +91,7 @@ func (m model) handleMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
 	} else if msg.X >= sidebarWidth { // Main view
 		m.focus = focusMain
 		m.textInput.Blur()
+		m.closePrompt()
 
 		var mainListItemsCount int
 		switch m.displayMode {
@@ -100,6 +104,17 @@ func (m model) handleMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
 			mainListItemsCount = len(m.albums)
 		case displayArtist:
 			mainListItemsCount = len(m.artists)
+		case displayAll:
+			mainListItemsCount = len(allRows(m))
+		}
+
+		// Sort by a clicked column header
+		if m.viewMode == viewList {
+			for _, column := range sortColumns(m) {
+				if column != sortNone && zone.Get(fmt.Sprintf("sort_%d", column)).InBounds(msg) {
+					return sortByColumn(m, column), nil
+				}
+			}
 		}
 
 		endIndex := m.mainOffset + mainHeight
@@ -175,12 +190,23 @@ func (m model) handleLoginResult(msg loginResultMsg) (tea.Model, tea.Cmd) {
 	m.focus = focusSearch
 	m.loginErr = ""
 
-	return m, tea.Batch(
+	cmds := []tea.Cmd{
 		syncPlayerCmd(),
 		getPlaylists(),
 		getPlayQueue(),
 		getStarredCmd(),
-	)
+	}
+
+	if len(api.AppState.Queue.UpNext) > 0 {
+		cmds = append(cmds, getUpNextCmd(api.AppState.Queue.UpNext))
+	}
+
+	if m.levelMeter {
+		player.SetLevelMeter(true)
+		cmds = append(cmds, syncLevelsCmd(m.levelMeterTick))
+	}
+
+	return m, tea.Batch(cmds...)
 }
 
 func (m model) handlePlaylistResult(msg playlistResultMsg) (tea.Model, tea.Cmd) {
@@ -202,7 +228,9 @@ func (m model) handleStatus(msg statusMsg) (tea.Model, tea.Cmd) {
 	if m.playerStatus.Path == "" || m.playerStatus.Path == "<nil>" || len(m.queue) == 0 {
 
 		m.queue = []api.Song{}
+		m.queueOriginal = nil
 		m.lastPlayedSongID = ""
+		cmds = append(cmds, m.finishListening())
 
 		// MRPIS Update
 		if m.dbusInstance != nil {
@@ -221,6 +249,13 @@ func (m model) handleStatus(msg statusMsg) (tea.Model, tea.Cmd) {
 			m.lastPlayedSongID = currentSong.ID
 			m.scrobbled = false
 
+			// Play history
+			cmds = append(cmds, tea.Sequence(
+				m.finishListening(),
+				recordHistoryCmd(historyEvent(api.HistoryStart, currentSong, 0)),
+			))
+			m.listening = currentSong
+
 			// Setup metadata
 			metadata := integration.Metadata{
 				Title:    currentSong.Title,
@@ -231,6 +266,14 @@ func (m model) handleStatus(msg statusMsg) (tea.Model, tea.Cmd) {
 				Rating:   math.Round(float64(currentSong.Rating*10)) / 10,
 			}
 
+			// ReplayGain and loudness normalization
+			m.syncAudioNormalization(currentSong)
+
+			cmds = append(cmds, m.requestCover(coverID(currentSong)))
+
+			// Remember for the smart shuffle
+			m.recentPlays = addRecentPlay(m.recentPlays, currentSong.ID)
+
 			// System notification
 			if m.notify {
 				go func() {
@@ -259,9 +302,19 @@ func (m model) handleStatus(msg statusMsg) (tea.Model, tea.Cmd) {
 
 			windowTitle := fmt.Sprintf("%s - %s", metadata.Title, metadata.Artist)
 			cmds = append(cmds, tea.SetWindowTitle(windowTitle))
+
+			// Last song of the queue started
+			if m.loopMode == LoopNone && m.followingIndex(m.queueIndex) == -1 && len(m.upNext) == 0 {
+				cmds = append(cmds, m.continueQueue(false))
+			}
 		}
 	}
 
+	// Track how far the song in the history got
+	if m.listening.ID != "" && strings.Contains(m.playerStatus.Path, "id="+m.listening.ID) {
+		m.trackListening(m.playerStatus.Current)
+	}
+
 	if len(m.queue) > 0 && m.queueIndex >= 0 && !m.scrobbled {
 		currentSong := m.queue[m.queueIndex]
 
@@ -275,6 +328,7 @@ func (m model) handleStatus(msg statusMsg) (tea.Model, tea.Cmd) {
 				m.scrobbled = true
 
 				go api.SubsonicScrobble(currentSong.ID, true)
+				cmds = append(cmds, recordHistoryCmd(historyEvent(api.HistoryScrobble, currentSong, pos)))
 			}
 		}
 	}
@@ -284,30 +338,28 @@ func (m model) handleStatus(msg statusMsg) (tea.Model, tea.Cmd) {
 		len(m.queue) > 0 &&
 		!strings.Contains(m.playerStatus.Path, "id="+m.queue[m.queueIndex].ID) {
 
-		nextIndex := m.queueIndex + 1
 		m.scrobbled = false
 
-		// Queue next song
-		if nextIndex < len(m.queue) {
-			m.queueIndex = nextIndex
+		// Songs from the up next queue play before the rest of the queue
+		if len(m.upNext) > 0 && strings.Contains(m.playerStatus.Path, "id="+m.upNext[0].ID) {
+			m.takeUpNext()
+			m.queueIndex++
+			m.syncNextSong()
+
+			cmds = append(cmds, m.savePlayQueue(), saveStateCmd())
+			return m, tea.Batch(cmds...)
 		}
 
-		nextNextIndex := -1
-		switch m.loopMode {
-		case LoopOne:
-			nextNextIndex = nextIndex
-		case LoopNone:
-			nextNextIndex = nextIndex + 1
-		case LoopAll:
-			if nextIndex == len(m.queue)-1 {
-				nextNextIndex = 0
-			} else {
-				nextNextIndex = nextIndex + 1
-			}
+		// Queue next song, the player went on with the song that was enqueued
+		if nextIndex := m.followingIndex(m.queueIndex); nextIndex != -1 {
+			m.queueIndex = nextIndex
 		}
+		nextNextIndex := m.followingIndex(m.queueIndex)
 
 		// Queue next next song
-		if nextNextIndex < len(m.queue) {
+		if id := m.upNextID(); id != "" {
+			player.UpdateNextSong(id)
+		} else if nextNextIndex != -1 {
 			player.UpdateNextSong(m.queue[nextNextIndex].ID)
 		} else { // End of queue, clear MPV
 			go player.UpdateNextSong("")
@@ -317,9 +369,30 @@ func (m model) handleStatus(msg statusMsg) (tea.Model, tea.Cmd) {
 	return m, tea.Batch(cmds...)
 }
 
+func (m model) handleAudioLevels(msg audioLevelsMsg) (tea.Model, tea.Cmd) {
+	// Stop polling once the meter is disabled or restarted
+	if !m.levelMeter || msg.tick != m.levelMeterTick {
+		return m, nil
+	}
+
+	m.audioLevels = msg.levels
+	return m, syncLevelsCmd(m.levelMeterTick)
+}
+
 func (m model) handleSongResult(msg songsResultMsg) (tea.Model, tea.Cmd) {
+	// The user already moved on to another list
+	if msg.gen != m.listGen {
+		return m, nil
+	}
+
+	// Sorting puts the items of a new page in between the ones already shown
+	kept := keepListItems(m)
+
 	m.loading = false
-	m.focus = focusMain
+	m.resetLists()
+	if m.focus != focusSearch {
+		m.focus = focusMain
+	}
 
 	songs := applyExclusionFilters(m, msg.songs)
 
@@ -329,16 +402,32 @@ func (m model) handleSongResult(msg songsResultMsg) (tea.Model, tea.Cmd) {
 		m.songs = songs
 		m.cursorMain = 0
 		m.mainOffset = 0
+		m.clearSelection()
 	}
 
 	m.pageHasMore = (len(songs) == 150)
+	m.filterLists()
+	if m.pageOffset > 0 {
+		m.restoreListItems(kept)
+	}
 
 	return m, nil
 }
 
 func (m model) handleAlbumResult(msg albumsResultMsg) (tea.Model, tea.Cmd) {
+	// The user already moved on to another list
+	if msg.gen != m.listGen {
+		return m, nil
+	}
+
+	// Sorting puts the items of a new page in between the ones already shown
+	kept := keepListItems(m)
+
 	m.loading = false
-	m.focus = focusMain
+	m.resetLists()
+	if m.focus != focusSearch {
+		m.focus = focusMain
+	}
 	m.pageHasMore = (len(msg.albums) == 150)
 
 	if m.pageOffset > 0 { // Append: paging
@@ -347,14 +436,31 @@ func (m model) handleAlbumResult(msg albumsResultMsg) (tea.Model, tea.Cmd) {
 		m.albums = msg.albums
 		m.cursorMain = 0
 		m.mainOffset = 0
+		m.clearSelection()
+	}
+
+	m.filterLists()
+	if m.pageOffset > 0 {
+		m.restoreListItems(kept)
 	}
 
 	return m, nil
 }
 
 func (m model) handleArtistsResult(msg artistsResultMsg) (tea.Model, tea.Cmd) {
+	// The user already moved on to another list
+	if msg.gen != m.listGen {
+		return m, nil
+	}
+
+	// Sorting puts the items of a new page in between the ones already shown
+	kept := keepListItems(m)
+
 	m.loading = false
-	m.focus = focusMain
+	m.resetLists()
+	if m.focus != focusSearch {
+		m.focus = focusMain
+	}
 	m.pageHasMore = (len(msg.artists) == 150)
 
 	if m.pageOffset > 0 { // Append: paging
@@ -363,11 +469,134 @@ func (m model) handleArtistsResult(msg artistsResultMsg) (tea.Model, tea.Cmd) {
 		m.artists = msg.artists
 		m.cursorMain = 0
 		m.mainOffset = 0
+		m.clearSelection()
+	}
+
+	m.filterLists()
+	if m.pageOffset > 0 {
+		m.restoreListItems(kept)
+	}
+
+	return m, nil
+}
+
+func (m model) handleAllResult(msg allResultMsg) (tea.Model, tea.Cmd) {
+	// The user already moved on to another list
+	if msg.gen != m.listGen {
+		return m, nil
+	}
+
+	m.loading = false
+	m.resetLists()
+	if m.focus != focusSearch {
+		m.focus = focusMain
+	}
+
+	m.allResults = msg.result
+	applyExclusionFilters(m, m.allResults.Songs)
+	m.pageHasMore = false
+	m.cursorMain = 0
+	m.mainOffset = 0
+	m.clearSelection()
+	m = skipAllHeader(m)
+
+	return m, nil
+}
+
+func (m model) handleCover(msg coverMsg) (tea.Model, tea.Cmd) {
+	// Dropped while it was loading
+	if _, ok := m.covers[msg.id]; !ok {
+		return m, nil
 	}
 
+	m.covers[msg.id] = msg.img
 	return m, nil
 }
 
+func (m model) handleSearchDebounce(msg searchDebounceMsg) (tea.Model, tea.Cmd) {
+	// Typing went on or the search was already sent
+	if msg.gen != m.typingGen || m.focus != focusSearch || m.prompt != promptNone || strings.TrimSpace(msg.query) == "" {
+		return m, nil
+	}
+
+	m, cmd := startSearch(m, msg.query)
+	m.viewTyped = true
+
+	return m, cmd
+}
+
+func (m model) handleRadioResult(msg radioResultMsg) (tea.Model, tea.Cmd) {
+	m.loading = false
+
+	size := api.AppConfig.App.RadioSize
+	if size <= 0 {
+		size = 50
+	}
+
+	seen := make(map[string]bool)
+
+	var radioSongs []api.Song
+	for _, song := range msg.songs {
+		if len(radioSongs) >= size {
+			break
+		}
+
+		if seen[song.ID] || isSongExcluded(m, song) {
+			continue
+		}
+
+		seen[song.ID] = true
+		radioSongs = append(radioSongs, song)
+	}
+
+	if len(radioSongs) == 0 {
+		return m.handleReport(reportMsg{title: "Radio", lines: []string{"The server found no similar songs"}})
+	}
+
+	// Shuffled like any other queue, only when shuffle is on
+	cmd := m.setQueueFrom(radioSongs, 0)
+
+	return m, cmd
+}
+
+func (m model) handleContinueQueue(msg continueQueueMsg) (tea.Model, tea.Cmd) {
+	m.continuing = false
+
+	inQueue := make(map[string]bool)
+	for _, song := range m.queue {
+		inQueue[song.ID] = true
+	}
+
+	var newSongs []api.Song
+	for _, song := range msg.songs {
+		if inQueue[song.ID] || isSongExcluded(m, song) {
+			continue
+		}
+
+		inQueue[song.ID] = true
+		song.AutoAdded = true
+		newSongs = append(newSongs, song)
+	}
+
+	if len(newSongs) == 0 {
+		return m, nil
+	}
+
+	m.recordQueue()
+	m.addToOriginal(newSongs, false)
+
+	startIndex := len(m.queue)
+	m.queue = append(m.queue, newSongs...)
+
+	// Start playing when the queue already ran out
+	if msg.play || playingQueueIndex(m) == -1 {
+		return m, m.playQueueIndex(startIndex, false)
+	}
+
+	m.syncNextSong()
+	return m, m.savePlayQueue()
+}
+
 func (m model) handleStarredResult(msg starredResultMsg) (tea.Model, tea.Cmd) {
 	for _, s := range msg.result.Songs {
 		m.starredMap[s.ID] = true
@@ -390,13 +619,18 @@ func (m model) handleViewStarredSongs(msg viewStarredSongsMsg) (tea.Model, tea.C
 		m.starredMap[a.ID] = true
 	}
 
+	m.resetLists()
 	m.songs = msg.Songs
+	m.clearSelection()
+	m.filterLists()
 	return m, nil
 }
 
 func (m model) handleShuffledSongs(msg shuffledSongsMsg) (tea.Model, tea.Cmd) {
 	if msg.updateView {
+		m.resetLists()
 		m.songs = msg.songs
+		m.clearSelection()
 	}
 
 	songs := applyExclusionFilters(m, msg.songs)
@@ -408,16 +642,18 @@ func (m model) handleShuffledSongs(msg shuffledSongsMsg) (tea.Model, tea.Cmd) {
 		}
 	}
 
-	shuffledQueue := make([]api.Song, len(filteredSongs))
-	copy(shuffledQueue, filteredSongs)
-
-	rand.Shuffle(len(shuffledQueue), func(i, j int) {
-		shuffledQueue[i], shuffledQueue[j] = shuffledQueue[j], shuffledQueue[i]
-	})
-
-	m.queue = shuffledQueue
+	m.recordQueue()
+	m.tagQueueEntries(filteredSongs)
+	m.queue = m.shuffleSongs(filteredSongs, nil)
+	m.queueIndex = 0
+	m.shuffled = true
+	m.queueOriginal = filteredSongs
 	m.loading = false
 
+	if msg.updateView {
+		m.filterLists()
+	}
+
 	return m, m.playQueueIndex(0, false)
 }
 
@@ -442,6 +678,148 @@ func (m model) handlePlayQueueResult(msg playQueueResultMsg) (tea.Model, tea.Cmd
 	return m, m.playQueueIndex(m.queueIndex, true)
 }
 
+func (m model) handleReport(msg reportMsg) (tea.Model, tea.Cmd) {
+	m.loading = false
+	m.syncPlans = nil
+	m.showReport = true
+	m.reportTitle = msg.title
+	m.reportLines = msg.lines
+
+	return m, nil
+}
+
+func (m model) handleImportResult(msg importResultMsg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	if len(msg.songs) > 0 {
+		m.recordQueue()
+		m.addToOriginal(msg.songs, false)
+
+		wasEmpty := len(m.queue) == 0
+		m.queue = append(m.queue, msg.songs...)
+
+		if wasEmpty {
+			cmd = m.playQueueIndex(0, false)
+		} else {
+			m.syncNextSong()
+		}
+	}
+
+	lines := []string{fmt.Sprintf("Added %d of %d songs from %s", len(msg.songs), len(msg.songs)+len(msg.unmatched), msg.path)}
+	if len(msg.unmatched) > 0 {
+		lines = append(lines, "", "Unmatched:")
+	}
+
+	for i, entry := range msg.unmatched {
+		name := entry.Title
+		if entry.Artist != "" {
+			name = entry.Artist + " - " + entry.Title
+		}
+		if name == "" {
+			name = entry.Path
+		}
+
+		log.Printf("[Import] Unmatched entry: %s", name)
+
+		if i < reportLimit {
+			lines = append(lines, name)
+		}
+	}
+
+	if len(msg.unmatched) > reportLimit {
+		lines = append(lines, fmt.Sprintf("... and %d more", len(msg.unmatched)-reportLimit))
+	}
+
+	model, reportCmd := m.handleReport(reportMsg{title: "Import", lines: lines})
+	return model, tea.Batch(cmd, reportCmd)
+}
+
+func (m model) handleSmartPlaylist(msg smartPlaylistMsg) (tea.Model, tea.Cmd) {
+	m.smartSongs[msg.name] = msg.songs
+
+	if msg.gen != m.listGen {
+		return m, nil
+	}
+
+	if msg.shuffled {
+		return m.handleShuffledSongs(shuffledSongsMsg{msg.songs, true})
+	}
+
+	m.pageOffset = 0
+	result, cmd := m.handleSongResult(songsResultMsg{songs: msg.songs, gen: msg.gen})
+
+	// The whole playlist is loaded at once
+	smartModel := result.(model)
+	smartModel.pageHasMore = false
+
+	return smartModel, cmd
+}
+
+func (m model) handleSyncPlan(msg syncPlanMsg) (tea.Model, tea.Cmd) {
+	var lines []string
+	changed := false
+
+	for _, plan := range msg.plans {
+		lines = append(lines, plan.Summary()...)
+		changed = changed || plan.Changed()
+	}
+
+	if len(lines) > reportLimit*2 {
+		lines = append(lines[:reportLimit*2], fmt.Sprintf("... and %d more", len(lines)-reportLimit*2))
+	}
+
+	if !changed {
+		return m.handleReport(reportMsg{title: "Sync", lines: append(lines, "", "All playlists are in sync")})
+	}
+
+	m.loading = false
+	m.showReport = true
+	m.reportTitle = "Sync Playlists"
+	m.reportLines = append(lines, "", "Press enter to apply")
+	m.syncPlans = msg.plans
+
+	return m, nil
+}
+
+func (m model) handleRecentPlays(msg recentPlaysMsg) (tea.Model, tea.Cmd) {
+	// Songs that started while the history loaded are the most recent
+	recent := msg.ids
+	for _, id := range m.recentPlays {
+		recent = addRecentPlay(recent, id)
+	}
+
+	m.recentPlays = recent
+	return m, nil
+}
+
+func (m model) handleHistoryResult(msg historyResultMsg) (tea.Model, tea.Cmd) {
+	m.loading = false
+	m.history = msg.events
+
+	if msg.stats {
+		m.stats = computeStats(m.history, statsPeriods[m.statsPeriod])
+		m.showStats = true
+		return m, nil
+	}
+
+	m.resetLists()
+	m.songs = historySongs(m.history)
+	m.clearSelection()
+	m.cursorMain = 0
+	m.mainOffset = 0
+	m.filterLists()
+
+	return m, nil
+}
+
+func (m model) handleUpNextResult(msg upNextResultMsg) (tea.Model, tea.Cmd) {
+	m.upNext = append(msg.songs, m.upNext...)
+	m.syncUpNextState()
+	m.syncNextSong()
+
+	return m, nil
+}
+
 func (m model) handleSetDBUS(msg SetDBusMsg) (tea.Model, tea.Cmd) {
 	m.dbusInstance = msg.Instance
 
@@ -455,7 +833,12 @@ func (m model) handleIntegrationPlayPause(msg integration.PlayPauseMsg) (tea.Mod
 }
 
 func (m model) handleIntegrationStop() (tea.Model, tea.Cmd) {
+	if len(m.queue) > 0 {
+		m.recordQueue()
+	}
+
 	m.queue = nil
+	m.queueOriginal = nil
 	player.Stop()
 
 	return m, nil
@@ -4,8 +4,11 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/MattiaPun/SubTUI/v2/internal/api"
+	"github.com/MattiaPun/SubTUI/v2/internal/art"
+	"github.com/MattiaPun/SubTUI/v2/internal/player"
 	"github.com/charmbracelet/lipgloss"
 	zone "github.com/lrstanley/bubblezone"
 	"github.com/mattn/go-runewidth"
@@ -28,11 +31,25 @@ const (
 	genreWeight  = 2.0
 )
 
+const upNextVisible = 3
+
 func (m model) View() string {
+	return drawCovers(m, m.screenView())
+}
+
+func (m model) screenView() string {
+	if showMini(m) {
+		return miniView(m)
+	}
+
 	if m.width < 50 || m.height < 25 {
 		return viewToSmallContent(m)
 	}
 
+	if m.showPlaying && m.viewMode != viewLogin {
+		return zone.Scan(nowPlayingView(m))
+	}
+
 	base := m.BaseView()
 
 	if m.showPlaylists {
@@ -70,6 +87,53 @@ func (m model) View() string {
 
 	}
 
+	if m.showStats {
+		content := statsContent(m)
+
+		styledContent := popupStyle.Render(
+			lipgloss.JoinVertical(lipgloss.Center,
+				lipgloss.NewStyle().Bold(true).Render("Listening Stats"),
+				"",
+				content,
+			),
+		)
+
+		fg := ContentModel{Content: styledContent}
+		bg := BackgroundWrapper{RenderedView: base}
+
+		return overlay.New(fg, bg, overlay.Center, overlay.Center, 0, 0).View()
+	}
+
+	if m.showProfiles {
+		styledContent := popupStyle.Render(
+			lipgloss.JoinVertical(lipgloss.Center,
+				lipgloss.NewStyle().Bold(true).Render("Filter Profile"),
+				"",
+				filterProfilesContent(m),
+			),
+		)
+
+		fg := ContentModel{Content: styledContent}
+		bg := BackgroundWrapper{RenderedView: base}
+
+		return overlay.New(fg, bg, overlay.Center, overlay.Center, 0, 0).View()
+	}
+
+	if m.showReport {
+		styledContent := popupStyle.Render(
+			lipgloss.JoinVertical(lipgloss.Center,
+				lipgloss.NewStyle().Bold(true).Render(m.reportTitle),
+				"",
+				lipgloss.NewStyle().Align(lipgloss.Left).Render(strings.Join(m.reportLines, "\n")),
+			),
+		)
+
+		fg := ContentModel{Content: styledContent}
+		bg := BackgroundWrapper{RenderedView: base}
+
+		return overlay.New(fg, bg, overlay.Center, overlay.Center, 0, 0).View()
+	}
+
 	if m.showHelp {
 		bg := BackgroundWrapper{RenderedView: base}
 		return overlay.New(m.helpModel, bg, overlay.Center, overlay.Center, 0, 0).View()
@@ -128,19 +192,40 @@ func (m model) BaseView() string {
 	}
 
 	mainContent := ""
+	coverShown := false
 	if m.loading &&
 		(m.displayMode == displaySongs && len(m.songs) == 0 ||
 			m.displayMode == displayAlbums && len(m.albums) == 0 ||
-			m.displayMode == displayArtist && len(m.artists) == 0) {
+			m.displayMode == displayArtist && len(m.artists) == 0 ||
+			m.displayMode == displayAll && len(allRows(m)) == 0) {
 		mainContent = "\n  Searching your library..."
+	} else if m.displayMode == displaySongs && m.viewMode == viewList && m.detailCover != "" && m.artProtocol != art.Off && mainWidth > 80 {
+		// Album cover next to its songs
+		list := func(width int) string { return mainSongsContent(m, width, mainHeight) }
+		mainContent = listWithCover(m, m.detailCover, list, mainWidth, mainHeight, sidebarWidth+3, headerHeight+3)
+		coverShown = true
+	} else if id := albumCover(m); id != "" && m.artProtocol != art.Off && mainWidth > 80 {
+		// Cover of the album under the cursor next to the albums
+		list := func(width int) string { return mainAlbumsContent(m, width, mainHeight) }
+		mainContent = listWithCover(m, id, list, mainWidth, mainHeight, sidebarWidth+3, headerHeight+3)
+		coverShown = true
 	} else if m.displayMode == displaySongs {
 		mainContent = mainSongsContent(m, mainWidth, mainHeight)
 	} else if m.displayMode == displayAlbums {
 		mainContent = mainAlbumsContent(m, mainWidth, mainHeight)
 	} else if m.displayMode == displayArtist {
 		mainContent = mainArtistContent(m, mainWidth, mainHeight)
+	} else if m.displayMode == displayAll {
+		mainContent = mainAllContent(m, mainWidth, mainHeight)
 	}
 
+	if !coverShown {
+		mainContent = art.Clear(m.artProtocol, coverSlotDetail) + mainContent
+	}
+
+	// Gone once the now playing view is closed
+	mainContent = art.Clear(m.artProtocol, coverSlotPlaying) + mainContent
+
 	rightPane := mainBorder.
 		Width(mainWidth).
 		Height(mainHeight).
@@ -155,10 +240,18 @@ func (m model) BaseView() string {
 		footerBorder = activeBorderStyle
 	}
 
+	footer := art.Clear(m.artProtocol, coverSlotFooter) + footerContent(m, m.width)
+	if id := playingCover(m); id != "" && m.artProtocol != art.Off && m.width > 60 {
+		// Cover of the playing song left of the song info
+		coverCols := footerHeight * 2
+		cover := coverBox(m, id, coverSlotFooter, coverCols, footerHeight, 1, headerHeight+mainHeight+5)
+		footer = lipgloss.JoinHorizontal(lipgloss.Top, cover, " ", footerContent(m, m.width-coverCols-1))
+	}
+
 	footerView := footerBorder.
 		Width(m.width - 2).
 		Height(footerHeight).
-		Render(footerContent(m))
+		Render(footer)
 
 	// COMBINE ALL VERTICALLY
 	return lipgloss.JoinVertical(lipgloss.Left,
@@ -249,17 +342,13 @@ func loginView(m model) string {
 
 func headerContent(m model) string {
 
-	leftContent := "Search: " + m.textInput.View()
-	filterMode := ""
-
-	switch m.filterMode {
-	case filterSongs:
-		filterMode = "Songs"
-	case filterAlbums:
-		filterMode = "Albums"
-	case filterArtist:
-		filterMode = "Artist"
+	leftContent := promptLabel(m.prompt) + m.textInput.View()
+	if m.prompt == promptFilter && m.liveError != "" {
+		leftContent += " " + m.liveError
+	} else if m.prompt == promptNone && m.focus != focusSearch && m.liveFilter != "" {
+		leftContent = promptLabel(promptFilter) + m.liveFilter
 	}
+	filterMode := filterModeLabel(m.filterMode)
 
 	rightContent := fmt.Sprintf("%s %s %s", zone.Mark("filter_prev", "<"), filterMode, zone.Mark("filter_next", ">"))
 
@@ -277,7 +366,7 @@ func sidebarContent(m model, mainHeight int, sidebarWidth int) string {
 	content := ""
 	currentLine := 0
 
-	totalItems := len(albumTypes) + len(m.playlists)
+	totalItems := sidebarLen(m)
 
 	for i := m.sideOffset; i < totalItems; i++ {
 		// Stop if run out of space - 1
@@ -295,8 +384,8 @@ func sidebarContent(m model, mainHeight int, sidebarWidth int) string {
 				// Not enough space for header + spacing
 				break
 			}
-		} else if i == len(albumTypes) {
-			header := lipgloss.NewStyle().Bold(true).Render("  PLAYLISTS")
+		} else if title := sidebarHeader(m, i); title != "" {
+			header := lipgloss.NewStyle().Bold(true).Render(title)
 
 			// If at top of view, use less padding above
 			if i == m.sideOffset {
@@ -323,12 +412,7 @@ func sidebarContent(m model, mainHeight int, sidebarWidth int) string {
 		}
 
 		// Item Logic
-		var name string
-		if i < len(albumTypes) {
-			name = albumTypes[i]
-		} else {
-			name = m.playlists[i-len(albumTypes)].Name
-		}
+		name := sidebarName(m, i)
 
 		cursor := "  "
 		style := lipgloss.NewStyle()
@@ -337,16 +421,49 @@ func sidebarContent(m model, mainHeight int, sidebarWidth int) string {
 			cursor = "> "
 		}
 
-		line := cursor + truncate(name, sidebarWidth-4)
+		// Display finder matches
+		marks := rowFindMarks(m, focusSidebar, i)
+		shown := truncate(name, sidebarWidth-4)
+		marks.column(cursor, shown, 0)
+		line := cursor + shown
 
 		id := fmt.Sprintf("sidebar_item_%d", i)
-		content += zone.Mark(id, style.Render(line)) + "\n"
+		content += zone.Mark(id, marks.render(style, line)) + "\n"
 		currentLine++
 	}
 
 	return content
 }
 
+// Helper: Line below the column headers, showing the way to the shown list
+func separatorLine(m model, mainWidth int) string {
+	line := strings.Repeat("-", mainWidth-4)
+
+	if crumbs := breadcrumb(m, mainWidth-10); crumbs != "" && m.viewMode == viewList {
+		line = "-- " + crumbs + " "
+		line += strings.Repeat("-", max(0, mainWidth-4-runewidth.StringWidth(line)))
+	}
+
+	return lipgloss.NewStyle().Foreground(Theme.Subtle).Render("  "+line) + "\n"
+}
+
+// Helper: Title of the sidebar section starting at an index
+func sidebarHeader(m model, i int) string {
+	smartStart := len(albumTypes) + len(m.playlists)
+	savedStart := smartStart + len(api.AppConfig.SmartPlaylists)
+
+	switch {
+	case i == len(albumTypes) && len(m.playlists) > 0:
+		return "  PLAYLISTS"
+	case i == smartStart && len(api.AppConfig.SmartPlaylists) > 0:
+		return "  SMART PLAYLISTS"
+	case i == savedStart && len(api.AppState.Search.Saved) > 0:
+		return "  SAVED SEARCHES"
+	}
+
+	return ""
+}
+
 func mainSongsContent(m model, mainWidth int, mainHeight int) string {
 	mainContent := ""
 	headerTitle := ""
@@ -362,6 +479,10 @@ func mainSongsContent(m model, mainWidth int, mainHeight int) string {
 		mainContent = "\n  Queue is empty."
 	}
 
+	if hasSelection(m) {
+		headerTitle += fmt.Sprintf(" [%d selected]", len(selectedIndices(m)))
+	}
+
 	if len(targetList) == 0 {
 		return mainContent
 	}
@@ -369,10 +490,16 @@ func mainSongsContent(m model, mainWidth int, mainHeight int) string {
 	cols := api.AppConfig.Columns
 	colTitle, colArtist, colAlbum, colGenre := calculateColumns(cols, mainWidth)
 
-	mainContent = generateHeader(cols, mainWidth, headerTitle)
-	mainContent += lipgloss.NewStyle().Foreground(Theme.Subtle).Render("  "+strings.Repeat("-", mainWidth-4)) + "\n"
+	mainContent = upNextContent(m, mainWidth)
+	state := sortState{}
+	if m.viewMode == viewList {
+		state = m.sorts[displaySongs]
+	}
 
-	headerHeight := 4
+	mainContent += generateHeader(cols, mainWidth, headerTitle, state)
+	mainContent += separatorLine(m, mainWidth)
+
+	headerHeight := 4 + upNextHeight(m)
 	visibleRows := mainHeight - headerHeight
 	if visibleRows < 1 {
 		visibleRows = 1
@@ -389,13 +516,24 @@ func mainSongsContent(m model, mainWidth int, mainHeight int) string {
 			break
 		}
 
+		// Hidden rows give their place to the next one
+		if isHiddenQueueRow(m, i) {
+			end++
+			continue
+		}
+
 		song := targetList[i]
 		rowText := ""
 		style := lipgloss.NewStyle()
 
 		// Display cursor
 		if m.cursorMain != i {
-			rowText += "  "
+			if isRowSelected(m, i) {
+				rowText += "* "
+				style = style.Foreground(Theme.Highlight)
+			} else {
+				rowText += "  "
+			}
 		} else {
 			rowText += "> "
 			if m.focus == focusMain {
@@ -408,10 +546,17 @@ func mainSongsContent(m model, mainWidth int, mainHeight int) string {
 		// Display favorited songs
 		if m.starredMap[song.ID] {
 			rowText += "♥ "
+		} else if m.viewMode == viewQueue && song.AutoAdded {
+			rowText += "+ "
 		} else {
 			rowText += "  "
 		}
 
+		// Display songs added by auto-continue
+		if m.viewMode == viewQueue && song.AutoAdded {
+			style = style.Italic(true)
+		}
+
 		// Display filtered out songs
 		if song.Filtered {
 			style = style.Foreground(Theme.Filtered)
@@ -435,16 +580,25 @@ func mainSongsContent(m model, mainWidth int, mainHeight int) string {
 			rowText += LimitString(trackStr, 4) + " "
 		}
 
+		// Display finder matches, the fields are searched as title, artist and album
+		marks := rowFindMarks(m, focusMain, i)
+
 		if cols.ShowTitle {
-			rowText += LimitString(song.Title, colTitle) + " "
+			title := LimitString(song.Title, colTitle)
+			marks.column(rowText, title, 0)
+			rowText += title + " "
 		}
 
 		if cols.ShowArtist {
-			rowText += LimitString(song.Artist, colArtist) + " "
+			artist := LimitString(song.Artist, colArtist)
+			marks.column(rowText, artist, 1)
+			rowText += artist + " "
 		}
 
 		if cols.ShowAlbum {
-			rowText += LimitString(song.Album, colAlbum) + " "
+			album := LimitString(song.Album, colAlbum)
+			marks.column(rowText, album, 2)
+			rowText += album + " "
 		}
 
 		if cols.ShowYear {
@@ -469,7 +623,7 @@ func mainSongsContent(m model, mainWidth int, mainHeight int) string {
 
 		// Add ID for mouse support
 		id := fmt.Sprintf("mainview_item_%d", i)
-		row := zone.Mark(id, style.Render(rowText))
+		row := zone.Mark(id, marks.render(style, rowText))
 
 		mainContent += fmt.Sprintf("%s\n", row)
 	}
@@ -477,6 +631,43 @@ func mainSongsContent(m model, mainWidth int, mainHeight int) string {
 	return mainContent
 }
 
+// Helper: Lines taken by the up next section of the queue view
+func upNextHeight(m model) int {
+	if m.viewMode != viewQueue || len(m.upNext) == 0 {
+		return 0
+	}
+
+	// Title, songs, overflow line and a blank line
+	height := 2 + min(len(m.upNext), upNextVisible)
+	if len(m.upNext) > upNextVisible {
+		height++
+	}
+
+	return height
+}
+
+func upNextContent(m model, mainWidth int) string {
+	if upNextHeight(m) == 0 {
+		return ""
+	}
+
+	subtle := lipgloss.NewStyle().Foreground(Theme.Subtle)
+
+	content := lipgloss.NewStyle().Bold(true).Render(fmt.Sprintf("  NEXT IN QUEUE (%d)", len(m.upNext))) + "\n"
+
+	for i, song := range m.upNext {
+		if i >= upNextVisible {
+			content += subtle.Render(fmt.Sprintf("  ... and %d more", len(m.upNext)-upNextVisible)) + "\n"
+			break
+		}
+
+		line := fmt.Sprintf("  %d. %s - %s", i+1, song.Title, song.Artist)
+		content += truncate(line, mainWidth-2) + "\n"
+	}
+
+	return content + "\n"
+}
+
 func mainAlbumsContent(m model, mainWidth int, mainHeight int) string {
 	if len(m.albums) == 0 {
 		return "\n  Use the search bar to find Albums."
@@ -486,15 +677,21 @@ func mainAlbumsContent(m model, mainWidth int, mainHeight int) string {
 	colAlbum := int(float64(availableWidth) * 0.45)
 	colArtist := int(float64(availableWidth) * 0.45)
 	colDuration := int(float64(availableWidth) * 0.1)
+	albumTitle := "ALBUM"
+	if hasSelection(m) {
+		albumTitle += fmt.Sprintf(" [%d selected]", len(selectedIndices(m)))
+	}
+
 	headerStyle := lipgloss.NewStyle().Bold(true).Foreground(Theme.Subtle)
+	state := m.sorts[displayAlbums]
 	header := fmt.Sprintf("  %s %s %s",
-		LimitString("ALBUM", colAlbum),
-		LimitString("ARTIST", colArtist),
-		LimitString("DURATION", colDuration),
+		sortLabel(albumTitle, colAlbum, sortAlbum, state),
+		sortLabel("ARTIST", colArtist, sortArtist, state),
+		sortLabel("DURATION", colDuration, sortDuration, state),
 	)
 
 	mainContent := headerStyle.Render(header) + "\n"
-	mainContent += lipgloss.NewStyle().Foreground(Theme.Subtle).Render("  "+strings.Repeat("-", mainWidth-4)) + "\n"
+	mainContent += separatorLine(m, mainWidth)
 
 	headerHeight := 4
 	visibleRows := mainHeight - headerHeight
@@ -518,6 +715,11 @@ func mainAlbumsContent(m model, mainWidth int, mainHeight int) string {
 		cursor := "  "
 		style := lipgloss.NewStyle()
 
+		if isRowSelected(m, i) {
+			cursor = "* "
+			style = style.Foreground(Theme.Highlight)
+		}
+
 		if m.cursorMain == i {
 			cursor = "> "
 			if m.focus == focusMain {
@@ -532,15 +734,19 @@ func mainAlbumsContent(m model, mainWidth int, mainHeight int) string {
 			starIcon = "♥"
 		}
 
-		row := fmt.Sprintf("%s %s %s %s",
-			starIcon, // 1 char
-			LimitString(album.Name, colAlbum-2),
-			LimitString(album.Artist, colArtist),
-			LimitString(formatTime(album.Duration), colDuration),
-		)
+		// Display finder matches, the fields are searched as name and artist
+		marks := rowFindMarks(m, focusMain, i)
+		name := LimitString(album.Name, colAlbum-2)
+		artist := LimitString(album.Artist, colArtist)
+
+		row := starIcon + " " // 1 char
+		marks.column(row, name, 0)
+		row += name + " "
+		marks.column(row, artist, 1)
+		row += artist + " " + LimitString(formatTime(album.Duration), colDuration)
 
 		id := fmt.Sprintf("mainview_item_%d", i)
-		row = zone.Mark(id, style.Render(row))
+		row = zone.Mark(id, marks.render(style, row))
 
 		mainContent += fmt.Sprintf("%s%s\n", cursor, style.Render(row))
 	}
@@ -555,10 +761,10 @@ func mainArtistContent(m model, mainWidth int, mainHeight int) string {
 
 	colArtist := mainWidth - 4
 	headerStyle := lipgloss.NewStyle().Bold(true).Foreground(Theme.Subtle)
-	header := fmt.Sprintf("  %s", LimitString("ARTIST", colArtist))
+	header := fmt.Sprintf("  %s", sortLabel("ARTIST", colArtist, sortArtist, m.sorts[displayArtist]))
 
 	mainContent := headerStyle.Render(header) + "\n"
-	mainContent += lipgloss.NewStyle().Foreground(Theme.Subtle).Render("  "+strings.Repeat("-", mainWidth-4)) + "\n"
+	mainContent += separatorLine(m, mainWidth)
 
 	headerHeight := 4
 	visibleRows := mainHeight - headerHeight
@@ -582,6 +788,11 @@ func mainArtistContent(m model, mainWidth int, mainHeight int) string {
 		cursor := "  "
 		style := lipgloss.NewStyle()
 
+		if isRowSelected(m, i) {
+			cursor = "* "
+			style = style.Foreground(Theme.Highlight)
+		}
+
 		if m.cursorMain == i {
 			cursor = "> "
 			if m.focus == focusMain {
@@ -596,10 +807,148 @@ func mainArtistContent(m model, mainWidth int, mainHeight int) string {
 			starIcon = lipgloss.NewStyle().Render("♥︎")
 		}
 
-		row := fmt.Sprintf("%s %s",
-			starIcon,
-			LimitString(artist.Name, colArtist-2),
-		)
+		// Display finder matches
+		marks := rowFindMarks(m, focusMain, i)
+		name := LimitString(artist.Name, colArtist-2)
+
+		row := starIcon + " "
+		marks.column(row, name, 0)
+		row += name
+
+		id := fmt.Sprintf("mainview_item_%d", i)
+		row = zone.Mark(id, marks.render(style, row))
+
+		mainContent += fmt.Sprintf("%s%s\n", cursor, style.Render(row))
+	}
+
+	return mainContent
+}
+
+func mainAllContent(m model, mainWidth int, mainHeight int) string {
+	rows := allRows(m)
+	if len(rows) == 0 {
+		if m.lastSearchQuery != "" {
+			return fmt.Sprintf("\n  Nothing found for \"%s\".", m.lastSearchQuery)
+		}
+		return "\n  Use the search bar to find Artists, Albums and Songs."
+	}
+
+	availableWidth := mainWidth - 4
+	colTitle := int(float64(availableWidth) * 0.45)
+	colArtist := int(float64(availableWidth) * 0.25)
+	colAlbum := int(float64(availableWidth) * 0.2)
+	colDuration := int(float64(availableWidth) * 0.1)
+
+	headerStyle := lipgloss.NewStyle().Bold(true).Foreground(Theme.Subtle)
+	header := fmt.Sprintf("  %s", LimitString(fmt.Sprintf("RESULTS FOR \"%s\"", m.lastSearchQuery), availableWidth))
+
+	mainContent := headerStyle.Render(header) + "\n"
+	mainContent += separatorLine(m, mainWidth)
+
+	headerHeight := 4
+	visibleRows := mainHeight - headerHeight
+	if visibleRows < 1 {
+		visibleRows = 1
+	}
+
+	start := m.mainOffset
+	end := start + visibleRows
+	if end >= len(rows) {
+		end = len(rows)
+	}
+
+	for i := start; i <= end; i++ {
+		if i >= len(rows) {
+			break
+		}
+
+		item := rows[i]
+
+		// Section titles are not selectable
+		if item.kind == allHeader {
+			title := "SONGS"
+			switch item.index {
+			case filterArtist:
+				title = "ARTISTS"
+			case filterAlbums:
+				title = "ALBUMS"
+			}
+
+			mainContent += headerStyle.Render("  "+title) + "\n"
+			continue
+		}
+
+		cursor := "  "
+		style := lipgloss.NewStyle()
+
+		if m.cursorMain == i {
+			cursor = "> "
+			if m.focus == focusMain {
+				style = style.Foreground(Theme.Highlight).Bold(true)
+			} else {
+				style = style.Foreground(Theme.Subtle)
+			}
+		}
+
+		row := ""
+		switch item.kind {
+		case allArtist:
+			artist := m.allResults.Artists[item.index]
+			starIcon := " "
+			if m.starredMap[artist.ID] {
+				starIcon = "♥"
+			}
+
+			row = fmt.Sprintf("%s %s", starIcon, LimitString(artist.Name, availableWidth-2))
+
+		case allAlbum:
+			album := m.allResults.Albums[item.index]
+			starIcon := " "
+			if m.starredMap[album.ID] {
+				starIcon = "♥"
+			}
+
+			row = fmt.Sprintf("%s %s %s %s",
+				starIcon,
+				LimitString(album.Name, colTitle-2),
+				LimitString(album.Artist, colArtist+colAlbum),
+				LimitString(formatTime(album.Duration), colDuration),
+			)
+
+		case allSong:
+			song := m.allResults.Songs[item.index]
+			starIcon := " "
+			if m.starredMap[song.ID] {
+				starIcon = "♥"
+			}
+
+			if song.Filtered && m.cursorMain != i {
+				style = style.Foreground(Theme.Filtered)
+			}
+
+			row = fmt.Sprintf("%s %s %s %s %s",
+				starIcon,
+				LimitString(song.Title, colTitle-2),
+				LimitString(song.Artist, colArtist),
+				LimitString(song.Album, colAlbum),
+				LimitString(formatTime(int64(song.Duration)), colDuration),
+			)
+
+		case allMore:
+			if m.cursorMain != i {
+				style = style.Foreground(Theme.Subtle)
+			}
+
+			more := "  Show all songs"
+			switch item.index {
+			case filterArtist:
+				more = "  Show all artists"
+			case filterAlbums:
+				more = "  Show all albums"
+			}
+
+			row = LimitString(more, availableWidth)
+		}
 
 		id := fmt.Sprintf("mainview_item_%d", i)
 		row = zone.Mark(id, style.Render(row))
@@ -610,7 +959,7 @@ func mainArtistContent(m model, mainWidth int, mainHeight int) string {
 	return mainContent
 }
 
-func footerContent(m model) string {
+func footerContent(m model, width int) string {
 	title := ""
 	artistAlbumText := ""
 
@@ -633,13 +982,18 @@ func footerContent(m model) string {
 	const borderWidth = 2
 	const spacing = 3
 
-	topRowGap := m.width - borderWidth - 2*spacing - len(notifyText) - len(title)
+	topRowGap := width - borderWidth - 2*spacing - len(notifyText) - len(title)
 
 	if topRowGap > 0 {
 		title += strings.Repeat(" ", topRowGap) + notifyText
 	}
 
-	barWidth := m.width - 20
+	meterText := ""
+	if m.levelMeter {
+		meterText = "  " + levelMeterContent(m.audioLevels, 8)
+	}
+
+	barWidth := width - 20 - lipgloss.Width(meterText)
 	if barWidth < 10 {
 		barWidth = 10
 	}
@@ -675,38 +1029,72 @@ func footerContent(m model) string {
 		loopText = "[Loop one]"
 	}
 
+	shuffleText := ""
+	if m.shuffled {
+		shuffleText = "[Shuffle]"
+	}
+
 	volumeText := ""
-	if m.playerStatus.Volume != 100 {
-		volumeText = fmt.Sprintf(" [%v%%]", m.playerStatus.Volume)
+	if m.playerStatus.Muted {
+		volumeText = "[Muted]"
+	} else if m.playerStatus.Volume != 100 {
+		volumeText = fmt.Sprintf("[%v%%]", m.playerStatus.Volume)
+	}
+
+	// Shuffle, loop and volume states are separated by a space, empty ones are left out
+	var states []string
+	for _, text := range []string{shuffleText, loopText, volumeText} {
+		if text != "" {
+			states = append(states, text)
+		}
 	}
+	stateText := strings.Join(states, " ")
 
 	bottomRowGap := 0
-	bottomRowSpaceTaken := borderWidth + 2*spacing + len(artistAlbumText) + len(loopText) + len(volumeText)
-	if artistAlbumText != "" && m.width != 0 && m.width-bottomRowSpaceTaken > 0 {
-		bottomRowGap = m.width - bottomRowSpaceTaken
-	} else if m.width != 0 {
-		bottomRowGap = m.width - borderWidth - 2*spacing - len(loopText)
+	bottomRowSpaceTaken := borderWidth + 2*spacing + len(artistAlbumText) + len(stateText)
+	if artistAlbumText != "" && width != 0 && width-bottomRowSpaceTaken > 0 {
+		bottomRowGap = width - bottomRowSpaceTaken
+	} else if width != 0 {
+		bottomRowGap = max(width-borderWidth-2*spacing-len(stateText), 0)
 	}
 
-	bottomRowText := artistAlbumText + strings.Repeat(" ", bottomRowGap) + loopText + volumeText
+	bottomRowText := artistAlbumText + strings.Repeat(" ", bottomRowGap) + stateText
 
-	topRow := lipgloss.NewStyle().Bold(true).Foreground(Theme.Highlight).Render("   " + LimitString(title, m.width-borderWidth-2*spacing))
-	bottomRow := lipgloss.NewStyle().Foreground(Theme.Subtle).Render("   " + LimitString(bottomRowText, m.width-borderWidth-2*spacing))
+	topRow := lipgloss.NewStyle().Bold(true).Foreground(Theme.Highlight).Render("   " + LimitString(title, width-borderWidth-2*spacing))
+	bottomRow := lipgloss.NewStyle().Foreground(Theme.Subtle).Render("   " + LimitString(bottomRowText, width-borderWidth-2*spacing))
 
-	rawProgress := fmt.Sprintf("%s %s %s",
+	rawProgress := fmt.Sprintf("%s %s %s%s",
 		currStr,
 		lipgloss.NewStyle().Foreground(Theme.Special).Render("["+barStr+"]"),
 		durStr,
+		meterText,
 	)
 
 	rowProgress := lipgloss.NewStyle().
-		Width(m.width - borderWidth).
+		Width(width - borderWidth).
 		Align(lipgloss.Center).
 		Render(rawProgress)
 
 	return fmt.Sprintf("%s\n%s\n\n%s", topRow, bottomRow, rowProgress)
 }
 
+// Helper: Render a stereo level meter
+func levelMeterContent(levels player.AudioLevels, width int) string {
+	bar := func(level float64) string {
+		filled := int((1 - level/player.SilenceLevel) * float64(width))
+		if filled < 0 {
+			filled = 0
+		} else if filled > width {
+			filled = width
+		}
+
+		return lipgloss.NewStyle().Foreground(Theme.Special).Render(strings.Repeat("█", filled)) +
+			lipgloss.NewStyle().Foreground(Theme.Subtle).Render(strings.Repeat("░", width-filled))
+	}
+
+	return fmt.Sprintf("L %s R %s", bar(levels.Left), bar(levels.Right))
+}
+
 func helpViewContent() string {
 	keyStyle := lipgloss.NewStyle().Foreground(Theme.Special).Bold(true)
 	descStyle := lipgloss.NewStyle().Foreground(Theme.Subtle)
@@ -745,12 +1133,24 @@ func helpViewContent() string {
 		line(keys(api.AppConfig.Keybinds.Navigation.Bottom), "Go to bottom"),
 		line(keys(api.AppConfig.Keybinds.Navigation.Select), "Select"),
 		line(keys(api.AppConfig.Keybinds.Navigation.PlayShuffled), "Start shuffled"),
+		line(keys(api.AppConfig.Keybinds.Navigation.ToggleSelect), "Select row"),
+		line(keys(api.AppConfig.Keybinds.Navigation.VisualMode), "Select range"),
+		line(keys(api.AppConfig.Keybinds.Navigation.SortBy), "Sort by next column"),
+		line(keys(api.AppConfig.Keybinds.Navigation.SortReverse), "Reverse sort"),
+		line(keys(api.AppConfig.Keybinds.Navigation.ViewBack), "Previous view"),
+		line(keys(api.AppConfig.Keybinds.Navigation.ViewForward), "Next view"),
 	)
 
 	searchKeybinds := section("SEARCH",
 		line(keys(api.AppConfig.Keybinds.Search.FocusSearch), "Focus search bar"),
+		line(keys(api.AppConfig.Keybinds.Search.FilterList), "Filter current list"),
+		line(keys(api.AppConfig.Keybinds.Search.Find), "Find in list"),
+		line(keys(api.AppConfig.Keybinds.Search.FindNext), "Next match"),
+		line(keys(api.AppConfig.Keybinds.Search.FindPrev), "Previous match"),
 		line(keys(api.AppConfig.Keybinds.Search.FilterNext), "Filter next"),
 		line(keys(api.AppConfig.Keybinds.Search.FilterPrev), "Filter prev"),
+		line(keys(api.AppConfig.Keybinds.Search.HistoryPrev), "Older search"),
+		line(keys(api.AppConfig.Keybinds.Search.HistoryNext), "Newer search"),
 	)
 
 	libraryKeybinds := section("LIBRARY",
@@ -758,29 +1158,40 @@ func helpViewContent() string {
 		line(keys(api.AppConfig.Keybinds.Library.AddRating), "Add rating"),
 		line(keys(api.AppConfig.Keybinds.Library.GoToAlbum), "Go to album"),
 		line(keys(api.AppConfig.Keybinds.Library.GoToArtist), "Go to artist"),
+		line(keys(api.AppConfig.Keybinds.Library.StartRadio), "Start radio"),
+		line(keys(api.AppConfig.Keybinds.Library.SyncPlaylists), "Sync local playlists"),
+		line(keys(api.AppConfig.Keybinds.Library.RefreshSmart), "Refresh smart playlists"),
+		line(keys(api.AppConfig.Keybinds.Library.SaveSearch), "Save/remove search"),
 	)
 
 	mediaKeybinds := section("MEDIA",
 		line(keys(api.AppConfig.Keybinds.Media.PlayPause), "Play/Pause"),
 		line(keys(api.AppConfig.Keybinds.Media.Next), "Next song"),
 		line(keys(api.AppConfig.Keybinds.Media.Prev), "Prev song"),
-		line(keys(api.AppConfig.Keybinds.Media.Shuffle), "Shuffle"),
+		line(keys(api.AppConfig.Keybinds.Media.Shuffle), "Toggle shuffle"),
 		line(keys(api.AppConfig.Keybinds.Media.Loop), "Loop mode"),
 		line(keys(api.AppConfig.Keybinds.Media.Restart), "Restart song"),
 		line(keys(api.AppConfig.Keybinds.Media.Rewind), "Rewind 10s"),
 		line(keys(api.AppConfig.Keybinds.Media.Forward), "Forward 10s"),
 		line(keys(api.AppConfig.Keybinds.Media.VolumeUp), "Volume up"),
 		line(keys(api.AppConfig.Keybinds.Media.VolumeDown), "Volume down"),
+		line(keys(api.AppConfig.Keybinds.Media.Mute), "Mute"),
 	)
 
 	queueKeybinds := section("QUEUE",
 		line(keys(api.AppConfig.Keybinds.Queue.ToggleQueueView), "Toggle queue view"),
-		line(keys(api.AppConfig.Keybinds.Queue.QueueNext), "Add next"),
+		line(keys(api.AppConfig.Keybinds.Queue.QueueNext), "Add to up next"),
 		line(keys(api.AppConfig.Keybinds.Queue.QueueLast), "Queue last"),
 		line(keys(api.AppConfig.Keybinds.Queue.RemoveFromQueue), "Remove from queue"),
 		line(keys(api.AppConfig.Keybinds.Queue.ClearQueue), "Clear queue"),
+		line(keys(api.AppConfig.Keybinds.Queue.RemoveUpNext), "Remove from up next"),
+		line(keys(api.AppConfig.Keybinds.Queue.ClearUpNext), "Clear up next"),
+		line(keys(api.AppConfig.Keybinds.Queue.Export), "Export queue/playlist"),
+		line(keys(api.AppConfig.Keybinds.Queue.Import), "Import into queue"),
 		line(keys(api.AppConfig.Keybinds.Queue.MoveUp), "Queue up"),
 		line(keys(api.AppConfig.Keybinds.Queue.MoveDown), "Queue down"),
+		line(keys(api.AppConfig.Keybinds.Queue.Undo), "Undo queue change"),
+		line(keys(api.AppConfig.Keybinds.Queue.Redo), "Redo queue change"),
 	)
 
 	starredKeybinds := section("FAVORITES",
@@ -788,9 +1199,23 @@ func helpViewContent() string {
 		line(keys(api.AppConfig.Keybinds.Favorites.ViewFavorites), "View fav"),
 	)
 
+	nowPlayingKeybinds := section("NOW PLAYING",
+		line(keys(api.AppConfig.Keybinds.NowPlaying.Toggle), "Toggle now playing"),
+		line(keys(api.AppConfig.Keybinds.NowPlaying.RateUp), "Rate higher"),
+		line(keys(api.AppConfig.Keybinds.NowPlaying.RateDown), "Rate lower"),
+		line(keys(api.AppConfig.Keybinds.NowPlaying.GoToAlbum), "Go to album"),
+	)
+
 	otherKeybinds := section("OTHERS",
 		line(keys(api.AppConfig.Keybinds.Other.ToggleNotifications), "Toggle notifications"),
 		line(keys(api.AppConfig.Keybinds.Other.CreateShareLink), "Create share link"),
+		line(keys(api.AppConfig.Keybinds.Other.ToggleLevelMeter), "Toggle level meter"),
+		line(keys(api.AppConfig.Keybinds.Other.ViewHistory), "Play history"),
+		line(keys(api.AppConfig.Keybinds.Other.ViewStats), "Listening stats"),
+		line(keys(api.AppConfig.Keybinds.Other.FilterProfiles), "Filter profiles"),
+		line(keys(api.AppConfig.Keybinds.Other.ToggleHideFiltered), "Hide filtered songs"),
+		line(keys(api.AppConfig.Keybinds.Other.WhyFiltered), "Why filtered"),
+		line(keys(api.AppConfig.Keybinds.Other.ToggleMini), "Mini player"),
 	)
 
 	columnLeft := lipgloss.JoinVertical(lipgloss.Left,
@@ -805,6 +1230,8 @@ func helpViewContent() string {
 		mediaKeybinds,
 		"", // spacer
 		navigationKeybinds,
+		"", // spacer
+		nowPlayingKeybinds,
 	)
 
 	columnRight := lipgloss.JoinVertical(lipgloss.Left,
@@ -843,6 +1270,84 @@ func addToPlaylistContent(m model) string {
 	return playlistContent
 }
 
+func statsContent(m model) string {
+	stats := m.stats
+	titleStyle := lipgloss.NewStyle().Foreground(Theme.Highlight).Bold(true)
+	subtle := lipgloss.NewStyle().Foreground(Theme.Subtle)
+
+	period := fmt.Sprintf("< %s >", statsPeriods[m.statsPeriod].name)
+
+	listened := time.Duration(stats.listened) * time.Second
+	summary := fmt.Sprintf("Plays: %d\nListening time: %dh %02dm\nSkips: %d (%d%%)",
+		stats.plays, int(listened.Hours()), int(listened.Minutes())%60, stats.skips, stats.skipRate())
+
+	// Helper to render a ranked list
+	ranking := func(title string, entries []statEntry, suffix string) string {
+		content := titleStyle.Render(title) + "\n"
+		if len(entries) == 0 {
+			return content + subtle.Render("-")
+		}
+
+		for i, entry := range entries {
+			content += fmt.Sprintf("%d. %s %s\n", i+1, LimitString(entry.name, 24), subtle.Render(fmt.Sprintf("(%d%s)", entry.count, suffix)))
+		}
+
+		return strings.TrimSuffix(content, "\n")
+	}
+
+	colStyle := lipgloss.NewStyle().MarginRight(4)
+
+	left := lipgloss.JoinVertical(lipgloss.Left,
+		ranking("Top Artists", stats.artists, ""),
+		"",
+		ranking("Top Genres", stats.genres, ""),
+	)
+
+	right := lipgloss.JoinVertical(lipgloss.Left,
+		ranking("Top Albums", stats.albums, ""),
+		"",
+		ranking("Most Skipped Artists", stats.skipRates, "%"),
+	)
+
+	return lipgloss.JoinVertical(lipgloss.Center,
+		titleStyle.Render(period),
+		"",
+		lipgloss.NewStyle().Align(lipgloss.Left).Render(summary),
+		"",
+		lipgloss.JoinHorizontal(lipgloss.Top, colStyle.Render(left), right),
+		"",
+		subtle.Render(fmt.Sprintf("%s/%s to change the period",
+			strings.Join(api.AppConfig.Keybinds.Navigation.Up, ","),
+			strings.Join(api.AppConfig.Keybinds.Navigation.Down, ","))),
+	)
+}
+
+func filterProfilesContent(m model) string {
+	content := ""
+	for i, name := range filterProfileNames() {
+		cursor := "  "
+		style := lipgloss.NewStyle()
+
+		if m.cursorPopup == i {
+			style = style.Foreground(Theme.Highlight).Bold(true)
+			cursor = "> "
+		}
+
+		active := ""
+		if name == m.filterProfile {
+			active = " ✓"
+		}
+
+		if name == "" {
+			name = "Default"
+		}
+
+		content += fmt.Sprintf("%s%s%s\n", cursor, style.Render(name), active)
+	}
+
+	return lipgloss.NewStyle().Align(lipgloss.Left).Render(content)
+}
+
 func addRatingContent(m model) string {
 	ratingContent := ""
 	for i := 0; i <= 5; i++ {
@@ -949,44 +1454,44 @@ func calculateColumns(cols api.Columns, mainWidth int) (int, int, int, int) {
 }
 
 // Helper: Generate header
-func generateHeader(cols api.Columns, mainWidth int, headerTitle string) string {
+func generateHeader(cols api.Columns, mainWidth int, headerTitle string, state sortState) string {
 	colTitle, colArtist, colAlbum, colGenre := calculateColumns(cols, mainWidth)
 
 	headerText := "  "
 	if cols.ShowTrackNumber {
-		headerText += LimitString("#", 4) + " "
+		headerText += sortLabel("#", 4, sortTrack, state) + " "
 	}
 
 	if cols.ShowTitle {
-		headerText += LimitString(headerTitle, colTitle) + " "
+		headerText += sortLabel(headerTitle, colTitle, sortTitle, state) + " "
 	}
 
 	if cols.ShowArtist {
-		headerText += LimitString("ARTIST", colArtist) + " "
+		headerText += sortLabel("ARTIST", colArtist, sortArtist, state) + " "
 	}
 
 	if cols.ShowAlbum {
-		headerText += LimitString("ALBUM", colAlbum) + " "
+		headerText += sortLabel("ALBUM", colAlbum, sortAlbum, state) + " "
 	}
 
 	if cols.ShowYear {
-		headerText += LimitString("YEAR", 4) + " "
+		headerText += sortLabel("YEAR", 4, sortYear, state) + " "
 	}
 
 	if cols.ShowGenre {
-		headerText += LimitString("GENRE", colGenre) + " "
+		headerText += sortLabel("GENRE", colGenre, sortGenre, state) + " "
 	}
 
 	if cols.ShowRating {
-		headerText += LimitString("RATE", 5) + " "
+		headerText += sortLabel("RATE", 5, sortRating, state) + " "
 	}
 
 	if cols.ShowPlayCount {
-		headerText += LimitString("PLAYS", 5) + " "
+		headerText += sortLabel("PLAYS", 5, sortPlays, state) + " "
 	}
 
 	if cols.ShowDuration {
-		headerText += LimitString("TIME", 6)
+		headerText += sortLabel("TIME", 6, sortDuration, state)
 	}
 
 	headerStyle := lipgloss.NewStyle().Bold(true).Foreground(Theme.Subtle)
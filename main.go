@@ -25,6 +25,7 @@ func main() {
 	// Debug flag
 	debug := flag.Bool("debug", false, "Enable debug logging to subtui.log")
 	showVersion := flag.Bool("v", false, "Print version and exit")
+	mini := flag.Bool("mini", false, "Start as a one or two line player")
 	flag.Parse()
 
 	// Check for version
@@ -58,11 +59,21 @@ func main() {
 		os.Exit(1)
 	}
 
+	// Load State
+	if err := api.LoadState(); err != nil {
+		log.Printf("Warning: failed to load state, using defaults: %v", err)
+	}
+
 	// Log Startup
 	if *debug {
 		log.Printf("Config Loaded: URL=%s User=%s", api.AppServerConfig.Server.URL, api.AppServerConfig.Server.Username)
 	}
 
+	// Run subcommands without the TUI
+	if args := flag.Args(); len(args) > 0 {
+		os.Exit(runCommand(args))
+	}
+
 	// Init variables
 	ui.InitStyles()
 	beeep.AppName = "SubTUI"
@@ -71,7 +82,7 @@ func main() {
 	defer player.ShutdownPlayer()
 
 	// Init TUI
-	p := tea.NewProgram(ui.InitialModel(), tea.WithAltScreen())
+	p := tea.NewProgram(ui.InitialModel(*mini), tea.WithAltScreen())
 
 	// Start background services
 	instance := integration.Init(p)